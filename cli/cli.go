@@ -3,34 +3,51 @@ package cli
 import (
 	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/VitoNaychev/eval-web-service/client"
 )
 
 type ExpressionClient interface {
-	Evaluate(string) (int, error)
+	Evaluate(string, client.EvaluateOptions) (client.Result, error)
 	Validate(string) (bool, error)
 	GetExpressionErrors() ([]client.ExpressionError, error)
 }
 
+// CLI reads commands from its input and writes their results to its output.
+// The expressions evaluated by one CLI share a session, so they can refer to
+// each other's variables and results. Results are displayed in the format
+// last chosen with the display command, as numbers by default.
 type CLI struct {
-	client ExpressionClient
-	in     *bufio.Scanner
-	out    *bufio.Writer
+	client  ExpressionClient
+	in      *bufio.Scanner
+	out     *bufio.Writer
+	session string
+	format  string
 }
 
 func NewCLI(client ExpressionClient, in io.Reader, out io.Writer) *CLI {
 	return &CLI{
-		client: client,
-		in:     bufio.NewScanner(in),
-		out:    bufio.NewWriter(out),
+		client:  client,
+		in:      bufio.NewScanner(in),
+		out:     bufio.NewWriter(out),
+		session: newSessionID(),
 	}
 }
 
+func newSessionID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
 func (c *CLI) Run(ctx context.Context) {
 	inputCh := make(chan string)
 	go readInput(c.in, inputCh)
@@ -80,12 +97,20 @@ func (c *CLI) executeCommand(cmd string) (string, error) {
 	switch {
 	case strings.HasPrefix(cmd, EvaluatePrefix):
 		expr := strings.TrimPrefix(cmd, EvaluatePrefix)
-		result, err := c.client.Evaluate(expr)
+		result, err := c.client.Evaluate(expr, client.EvaluateOptions{Session: c.session, Format: c.format})
 		if err != nil {
-			return "", err
+			return "", withCaret(expr, err)
 		}
 
-		output = fmt.Sprintln(result)
+		output = formatSentences(expr, result)
+	case strings.HasPrefix(cmd, TracePrefix):
+		expr := strings.TrimPrefix(cmd, TracePrefix)
+		result, err := c.client.Evaluate(expr, client.EvaluateOptions{Session: c.session, Format: c.format, Trace: true})
+		if err != nil {
+			return "", withCaret(expr, err)
+		}
+
+		output = formatSentences(expr, result)
 	case strings.HasPrefix(cmd, ValidatePrefix):
 		expr := strings.TrimPrefix(cmd, ValidatePrefix)
 		isValid, err := c.client.Validate(expr)
@@ -94,6 +119,16 @@ func (c *CLI) executeCommand(cmd string) (string, error) {
 		}
 
 		output = fmt.Sprintln(isValid)
+	case strings.HasPrefix(cmd, DisplayPrefix):
+		format := strings.TrimSpace(strings.TrimPrefix(cmd, DisplayPrefix))
+		switch format {
+		case client.NumberFormat, client.SentenceFormat, client.WordsFormat:
+			c.format = format
+		default:
+			return "", errors.New("unknown format")
+		}
+
+		output = fmt.Sprintln("displaying results as " + format)
 	case strings.HasPrefix(cmd, ExpressionErrorsPrefix):
 		exprErrors, err := c.client.GetExpressionErrors()
 		if err != nil {
@@ -110,7 +145,75 @@ func (c *CLI) executeCommand(cmd string) (string, error) {
 	return output, nil
 }
 
+// formatResult prints the answer to a result when there is one. Otherwise
+// it answers yes/no questions with "yes" or "no" and prints any other result
+// as is.
+func formatResult(r client.Result) string {
+	if r.Answer != "" {
+		return r.Answer
+	}
+	if r.Type != client.BooleanResult {
+		return r.Value
+	}
+
+	if r.Value == "true" {
+		return "yes"
+	}
+	return "no"
+}
+
+// formatTrace prints the steps of an evaluation, one indented line each.
+func formatTrace(r client.Result) string {
+	var output string
+	for _, step := range r.Trace {
+		output += fmt.Sprintln("\t" + step.Text)
+	}
+	return output
+}
+
+// formatSentences prints the result of an expression, or one line per
+// sentence when it is made of several of them. The steps of a result that
+// holds a trace are printed before it.
+func formatSentences(expr string, r client.Result) string {
+	if r.Sentences == nil {
+		return formatTrace(r) + fmt.Sprintln(formatResult(r))
+	}
+
+	var output string
+	for _, sentence := range r.Sentences {
+		if sentence.Err != nil {
+			output += fmt.Sprintln("error: " + withCaret(expr, sentence.Err).Error())
+			continue
+		}
+		output += formatTrace(sentence.Result) + fmt.Sprintln(formatResult(sentence.Result))
+	}
+	return output
+}
+
 func formatExpressionError(e client.ExpressionError) string {
 	return fmt.Sprintf("\t\"%s\"; on %s; %d times; %s\n",
 		e.Expression, e.Method, e.Frequency, e.Type)
 }
+
+// withCaret appends the expression to errors that carry a diagnostic, with
+// carets underlining the offending text.
+func withCaret(expr string, err error) error {
+	var diagnosticErr *client.DiagnosticError
+	if !errors.As(err, &diagnosticErr) {
+		return err
+	}
+
+	diagnostic := diagnosticErr.Diagnostic
+	if diagnostic.Offset+diagnostic.Length > len(expr) {
+		return err
+	}
+
+	padding := utf8.RuneCountInString(expr[:diagnostic.Offset])
+	width := utf8.RuneCountInString(expr[diagnostic.Offset : diagnostic.Offset+diagnostic.Length])
+	if width == 0 {
+		width = 1
+	}
+
+	return fmt.Errorf("%w\n\t%s\n\t%s%s", err, expr,
+		strings.Repeat(" ", padding), strings.Repeat("^", width))
+}
@@ -15,17 +15,24 @@ import (
 )
 
 type StubExpressionClient struct {
-	result     int
+	result     client.Result
 	isValid    bool
 	exprErrors []client.ExpressionError
+	err        error
 
 	spyEvaluateExpr string
 	spyValidateExpr string
+	spySessions     []string
+	spyFormat       string
+	spyTrace        bool
 }
 
-func (s *StubExpressionClient) Evaluate(expr string) (int, error) {
+func (s *StubExpressionClient) Evaluate(expr string, opts client.EvaluateOptions) (client.Result, error) {
 	s.spyEvaluateExpr = expr
-	return s.result, nil
+	s.spySessions = append(s.spySessions, opts.Session)
+	s.spyFormat = opts.Format
+	s.spyTrace = opts.Trace
+	return s.result, s.err
 }
 
 func (s *StubExpressionClient) Validate(expr string) (bool, error) {
@@ -91,12 +98,12 @@ func TestCLI(t *testing.T) {
 	t.Run("evaluates a math expression", func(t *testing.T) {
 		expr := "What is 5 plus 10?"
 		cmd := fmt.Sprint(".. ", expr)
-		wantResult := 15
+		wantResult := "15"
 		in := strings.NewReader(cmd + "\n" + exitCmd)
 		out := bytes.NewBuffer([]byte{})
 
 		exprClient := &StubExpressionClient{
-			result: wantResult,
+			result: client.Result{Value: wantResult},
 		}
 
 		exprCli := cli.NewCLI(exprClient, in, out)
@@ -107,6 +114,22 @@ func TestCLI(t *testing.T) {
 		assert.Equal(t, out.String(), fmt.Sprintln(wantResult))
 	})
 
+	t.Run("answers yes/no questions with yes or no", func(t *testing.T) {
+		cmd := cli.EvaluatePrefix + "Is 5 greater than 3?"
+		in := strings.NewReader(cmd + "\n" + exitCmd)
+		out := bytes.NewBuffer([]byte{})
+
+		exprClient := &StubExpressionClient{
+			result: client.Result{Value: "true", Type: client.BooleanResult},
+		}
+
+		cli.NewCLI(exprClient, in, out).Run(context.Background())
+
+		if !strings.Contains(out.String(), "yes\n") {
+			t.Errorf("got %q want it to contain %q", out.String(), "yes\n")
+		}
+	})
+
 	t.Run("validates a math expresion", func(t *testing.T) {
 		expr := "What is 5 plus 10?"
 		cmd := fmt.Sprint("?? ", expr)
@@ -177,4 +200,139 @@ func TestCLI(t *testing.T) {
 
 		assert.Equal(t, out.String(), "unknown command")
 	})
+
+	t.Run("underlines the offending text of an error with a diagnostic", func(t *testing.T) {
+		expr := "What is 5 plus plus 3?"
+		cmd := cli.EvaluatePrefix + expr
+		in := strings.NewReader(cmd + "\n" + exitCmd)
+		out := bytes.NewBuffer([]byte{})
+
+		exprClient := &StubExpressionClient{
+			err: client.NewDiagnosticError(client.ErrInvalidSyntax, client.Diagnostic{
+				Offset: 15,
+				Length: 4,
+				Text:   "plus",
+			}),
+		}
+		wantErrOutput := "error: invalid syntax\n\t" + expr + "\n\t               ^^^^\n"
+
+		exprCli := cli.NewCLI(exprClient, in, out)
+
+		exprCli.Run(context.Background())
+
+		if !strings.Contains(out.String(), wantErrOutput) {
+			t.Errorf("got %q want it to contain %q", out.String(), wantErrOutput)
+		}
+	})
+
+	t.Run("prints one line per sentence", func(t *testing.T) {
+		expr := "What is 1 plus 1? What is 2 plus? Is 2 greater than 1?"
+		cmd := cli.EvaluatePrefix + expr
+		in := strings.NewReader(cmd + "\n" + exitCmd)
+		out := bytes.NewBuffer([]byte{})
+
+		exprClient := &StubExpressionClient{
+			result: client.Result{Sentences: []client.SentenceResult{
+				{Sentence: "What is 1 plus 1?", Result: client.Result{Value: "2"}},
+				{Sentence: "What is 2 plus?", Err: client.NewDiagnosticError(client.ErrInvalidSyntax, client.Diagnostic{
+					Offset: 32,
+					Length: 1,
+					Text:   "?",
+				})},
+				{Sentence: "Is 2 greater than 1?", Result: client.Result{Value: "true", Type: client.BooleanResult}},
+			}},
+		}
+		wantOutput := "2\nerror: invalid syntax\n\t" + expr + "\n\t" + strings.Repeat(" ", 32) + "^\nyes\n"
+
+		cli.NewCLI(exprClient, in, out).Run(context.Background())
+
+		if !strings.Contains(out.String(), wantOutput) {
+			t.Errorf("got %q want it to contain %q", out.String(), wantOutput)
+		}
+	})
+
+	t.Run("displays answers in the chosen format", func(t *testing.T) {
+		cmd := cli.DisplayPrefix + "words\n" + cli.EvaluatePrefix + "What is 5 plus 3?"
+		in := strings.NewReader(cmd + "\n" + exitCmd)
+		out := bytes.NewBuffer([]byte{})
+
+		exprClient := &StubExpressionClient{
+			result: client.Result{Value: "8", Answer: "Five plus three is eight."},
+		}
+
+		cli.NewCLI(exprClient, in, out).Run(context.Background())
+
+		assert.Equal(t, exprClient.spyFormat, client.WordsFormat)
+		if !strings.Contains(out.String(), "Five plus three is eight.\n") {
+			t.Errorf("got %q want it to contain %q", out.String(), "Five plus three is eight.\n")
+		}
+	})
+
+	t.Run("prints an error on unknown display format", func(t *testing.T) {
+		cmd := cli.DisplayPrefix + "poem"
+		in := strings.NewReader(cmd + "\n" + exitCmd)
+		out := bytes.NewBuffer([]byte{})
+
+		cli.NewCLI(&StubExpressionClient{}, in, out).Run(context.Background())
+
+		if !strings.Contains(out.String(), "error: unknown format\n") {
+			t.Errorf("got %q want it to contain %q", out.String(), "error: unknown format\n")
+		}
+	})
+
+	t.Run("prints the trace of an evaluation", func(t *testing.T) {
+		cmd := cli.TracePrefix + "What is (2 plus 3) multiplied by 4?"
+		in := strings.NewReader(cmd + "\n" + exitCmd)
+		out := bytes.NewBuffer([]byte{})
+
+		exprClient := &StubExpressionClient{
+			result: client.Result{Value: "20", Trace: []client.Step{
+				{Text: "2 plus 3 = 5"},
+				{Text: "5 multiplied by 4 = 20"},
+			}},
+		}
+		wantOutput := "\t2 plus 3 = 5\n\t5 multiplied by 4 = 20\n20\n"
+
+		cli.NewCLI(exprClient, in, out).Run(context.Background())
+
+		assert.Equal(t, exprClient.spyTrace, true)
+		assert.Equal(t, exprClient.spyEvaluateExpr, "What is (2 plus 3) multiplied by 4?")
+		if !strings.Contains(out.String(), wantOutput) {
+			t.Errorf("got %q want it to contain %q", out.String(), wantOutput)
+		}
+	})
+
+	t.Run("evaluates expressions in one session", func(t *testing.T) {
+		cmd := cli.EvaluatePrefix + "What is 5 plus 3?\n" + cli.EvaluatePrefix + "What is the result multiplied by 2?"
+		in := strings.NewReader(cmd + "\n" + exitCmd)
+		out := bytes.NewBuffer([]byte{})
+
+		exprClient := &StubExpressionClient{}
+		otherClient := &StubExpressionClient{}
+
+		cli.NewCLI(exprClient, in, out).Run(context.Background())
+		cli.NewCLI(otherClient, strings.NewReader(cmd+"\n"+exitCmd), out).Run(context.Background())
+
+		assert.Equal(t, len(exprClient.spySessions), 2)
+		assert.Equal(t, exprClient.spySessions[0] != "", true)
+		assert.Equal(t, exprClient.spySessions[1], exprClient.spySessions[0])
+		assert.Equal(t, otherClient.spySessions[0] != exprClient.spySessions[0], true)
+	})
+
+	t.Run("prints an error when there is no previous result", func(t *testing.T) {
+		cmd := cli.EvaluatePrefix + "What is the result plus 1?"
+		in := strings.NewReader(cmd + "\n" + exitCmd)
+		out := bytes.NewBuffer([]byte{})
+
+		exprClient := &StubExpressionClient{
+			err: client.ErrNoPreviousResult,
+		}
+		wantErrOutput := "error: no previous result\n"
+
+		cli.NewCLI(exprClient, in, out).Run(context.Background())
+
+		if !strings.Contains(out.String(), wantErrOutput) {
+			t.Errorf("got %q want it to contain %q", out.String(), wantErrOutput)
+		}
+	})
 }
@@ -4,6 +4,8 @@ const (
 	PromptSymbol = ">>>"
 
 	EvaluatePrefix         = ".. "
+	TracePrefix            = "** "
 	ValidatePrefix         = "?? "
 	ExpressionErrorsPrefix = "!!"
+	DisplayPrefix          = "\\d "
 )
@@ -2,6 +2,7 @@ package client
 
 type ClientError struct {
 	msg string
+	err error
 }
 
 func NewClientError(msg string) error {
@@ -10,19 +11,162 @@ func NewClientError(msg string) error {
 	}
 }
 
+// NewSyntaxError returns an error that describes a specific syntax problem,
+// e.g. "invalid syntax: unclosed group", while still matching
+// ErrInvalidSyntax through errors.Is.
+func NewSyntaxError(msg string) error {
+	return &ClientError{
+		msg: ErrInvalidSyntax.Error() + ": " + msg,
+		err: ErrInvalidSyntax,
+	}
+}
+
 func (c *ClientError) Error() string {
 	return c.msg
 }
 
+func (c *ClientError) Unwrap() error {
+	return c.err
+}
+
 var (
 	ErrNonMathQuestion      = NewClientError("non-math question")
 	ErrUnsupportedOperation = NewClientError("unsupported operation")
 	ErrInvalidSyntax        = NewClientError("invalid syntax")
+	ErrDivisionByZero       = NewClientError("division by zero")
+	ErrOverflow             = NewClientError("overflow")
+	ErrDomain               = NewClientError("math domain error")
+	ErrUndefinedVariable    = NewClientError("undefined variable")
+	ErrNoPreviousResult     = NewClientError("no previous result")
+	ErrDimensionMismatch    = NewClientError("dimension mismatch")
+	ErrInvalidDate          = NewClientError("invalid date")
+	ErrUnknownTimeZone      = NewClientError("unknown time zone")
+	ErrUnsupportedLocale    = NewClientError("unsupported locale")
+
+	ErrUnclosedGroup = NewSyntaxError("unclosed group")
+	ErrUnopenedGroup = NewSyntaxError("closing an unopened group")
+	ErrEmptyGroup    = NewSyntaxError("empty group")
 )
 
+// Diagnostic locates the part of an expression that caused an error.
+// Offset and Length are in bytes. Suggestions lists the known words closest
+// to a word the server couldn't read.
+type Diagnostic struct {
+	Offset      int
+	Length      int
+	Text        string
+	Expected    []string
+	Suggestions []string
+}
+
+// DiagnosticError attaches a Diagnostic to one of the client errors. It
+// reports the message of the error it wraps and matches it through
+// errors.Is.
+type DiagnosticError struct {
+	err        error
+	Diagnostic Diagnostic
+}
+
+func NewDiagnosticError(err error, diagnostic Diagnostic) error {
+	return &DiagnosticError{
+		err:        err,
+		Diagnostic: diagnostic,
+	}
+}
+
+func (d *DiagnosticError) Error() string {
+	return d.err.Error()
+}
+
+func (d *DiagnosticError) Unwrap() error {
+	return d.err
+}
+
 type ExpressionError struct {
 	Expression string
 	Method     string
 	Frequency  int
 	Type       string
 }
+
+const (
+	IntegerArithmetic    = "integer"
+	FloatArithmetic      = "float"
+	DecimalArithmetic    = "decimal"
+	RationalArithmetic   = "rational"
+	BigIntegerArithmetic = "big-integer"
+)
+
+// EvaluateOptions configures an evaluation. Evaluations made with the same
+// Session share variables and can refer to each other's results. TimeZone is
+// the IANA name of the time zone dates are evaluated in, e.g. "Europe/Sofia",
+// and is left empty for UTC. Format is one of NumberFormat, SentenceFormat
+// and WordsFormat, and is left empty for NumberFormat. Trace asks for the
+// steps of the evaluation. Order is PrecedenceOrder, or LeftToRightOrder to
+// apply operations strictly left to right, and is left empty for
+// PrecedenceOrder. Locale is the language tag of the language the expression
+// is written in, e.g. "de", and is left empty to let the server read it in
+// English.
+type EvaluateOptions struct {
+	Arithmetic string
+	Order      string
+	Session    string
+	TimeZone   string
+	Format     string
+	Trace      bool
+	Locale     string
+}
+
+const (
+	PrecedenceOrder  = "precedence"
+	LeftToRightOrder = "left-to-right"
+)
+
+const (
+	NumberFormat   = "number"
+	SentenceFormat = "sentence"
+	WordsFormat    = "words"
+)
+
+const (
+	NumberResult  = "number"
+	BooleanResult = "boolean"
+	DateResult    = "date"
+)
+
+// Result is the value of an evaluated expression. Type is NumberResult,
+// DateResult when Value is a date, e.g. "2024-04-15", or BooleanResult when
+// Value is the "true" or "false" answer to a yes/no question.
+// Answer is the result written as a sentence, e.g. "5 plus 3 is 8.", when
+// the options ask for one, and Trace lists the steps of the evaluation when
+// they ask for a trace. Sentences holds the outcome of each sentence of an
+// expression made of several sentences, e.g. "What is 1 plus 1? What is 2
+// plus 2?", and is nil otherwise.
+type Result struct {
+	Value      string
+	Type       string
+	Arithmetic string
+	Answer     string
+	Trace      []Step
+	Sentences  []SentenceResult
+}
+
+// Step is one operation applied while evaluating an expression. Text shows
+// the working, e.g. "2 plus 3 = 5", and Offset and Length locate the
+// operator in the expression, in bytes.
+type Step struct {
+	Text     string
+	Operator string
+	Operands []string
+	Result   string
+	Offset   int
+	Length   int
+}
+
+// SentenceResult is the outcome of one sentence of an expression. Either
+// Result or Err is set.
+type SentenceResult struct {
+	Sentence string
+	Result   Result
+	Err      error
+}
@@ -10,6 +10,7 @@ import (
 type Client interface {
 	Post(string, string, io.Reader) (*http.Response, error)
 	Get(string) (*http.Response, error)
+	Do(*http.Request) (*http.Response, error)
 }
 
 type ExpressionHTTPClient struct {
@@ -24,24 +25,97 @@ func NewExpressionHTTPClient(client Client, url string) *ExpressionHTTPClient {
 	}
 }
 
-func (e *ExpressionHTTPClient) Evaluate(expr string) (int, error) {
+func (e *ExpressionHTTPClient) Evaluate(expr string, opts EvaluateOptions) (Result, error) {
 	expressionRequest := ExpressionRequest{
 		Expression: expr,
+		Arithmetic: opts.Arithmetic,
+		Order:      opts.Order,
+		TimeZone:   opts.TimeZone,
+		Format:     opts.Format,
+		Trace:      opts.Trace,
+		Locale:     opts.Locale,
 	}
 
 	body := bytes.NewBuffer([]byte{})
 	json.NewEncoder(body).Encode(expressionRequest)
 
-	response, _ := e.client.Post(e.url+EvaluateURL, "application/json", body)
+	request, err := http.NewRequest(http.MethodPost, e.url+EvaluateURL, body)
+	if err != nil {
+		return Result{}, NewClientError(err.Error())
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if opts.Session != "" {
+		request.Header.Set(SessionHeader, opts.Session)
+	}
+
+	response, _ := e.client.Do(request)
 
 	if response.StatusCode != 200 {
-		return -1, handleServerError(response)
+		return Result{}, handleServerError(response)
 	}
 
 	var evaluateResponse EvaluateResponse
 	json.NewDecoder(response.Body).Decode(&evaluateResponse)
 
-	return evaluateResponse.Result, nil
+	return evaluateResponseToResult(evaluateResponse), nil
+}
+
+func evaluateResponseToResult(r EvaluateResponse) Result {
+	result := Result{
+		Value:      string(r.Result),
+		Type:       r.Type,
+		Arithmetic: r.Arithmetic,
+		Answer:     r.Answer,
+		Trace:      stepResponsesToSteps(r.Trace),
+	}
+	for _, sentence := range r.Sentences {
+		result.Sentences = append(result.Sentences, sentenceResponseToSentenceResult(sentence))
+	}
+	return result
+}
+
+func sentenceResponseToSentenceResult(r SentenceResponse) SentenceResult {
+	if r.Error != "" {
+		return SentenceResult{
+			Sentence: r.Sentence,
+			Err:      errorResponseToClientError(ErrorResponse{Error: r.Error, Diagnostic: r.Diagnostic}),
+		}
+	}
+
+	return SentenceResult{
+		Sentence: r.Sentence,
+		Result: Result{
+			Value:      string(r.Result),
+			Type:       r.Type,
+			Arithmetic: r.Arithmetic,
+			Answer:     r.Answer,
+			Trace:      stepResponsesToSteps(r.Trace),
+		},
+	}
+}
+
+func stepResponsesToSteps(responses []StepResponse) []Step {
+	if responses == nil {
+		return nil
+	}
+
+	steps := make([]Step, len(responses))
+	for i, r := range responses {
+		operands := make([]string, len(r.Operands))
+		for j, operand := range r.Operands {
+			operands[j] = string(operand)
+		}
+
+		steps[i] = Step{
+			Text:     r.Text,
+			Operator: r.Operator,
+			Operands: operands,
+			Result:   string(r.Result),
+			Offset:   r.Offset,
+			Length:   r.Length,
+		}
+	}
+	return steps
 }
 
 func (e *ExpressionHTTPClient) Validate(expr string) (bool, error) {
@@ -99,10 +173,29 @@ func handleServerError(response *http.Response) error {
 	var errorResponse ErrorResponse
 	json.NewDecoder(response.Body).Decode(&errorResponse)
 
-	if response.StatusCode == http.StatusBadRequest {
-		return errorMessageToClientError(errorResponse.Error)
+	if response.StatusCode != http.StatusBadRequest {
+		return NewClientError(errorResponse.Error)
+	}
+
+	return errorResponseToClientError(errorResponse)
+}
+
+func errorResponseToClientError(r ErrorResponse) error {
+	err := errorMessageToClientError(r.Error)
+	if r.Diagnostic == nil {
+		return err
+	}
+	return NewDiagnosticError(err, diagnosticResponseToDiagnostic(*r.Diagnostic))
+}
+
+func diagnosticResponseToDiagnostic(r DiagnosticResponse) Diagnostic {
+	return Diagnostic{
+		Offset:      r.Offset,
+		Length:      r.Length,
+		Text:        r.Text,
+		Expected:    r.Expected,
+		Suggestions: r.Suggestions,
 	}
-	return NewClientError(errorResponse.Error)
 }
 
 func errorMessageToClientError(msg string) error {
@@ -113,6 +206,30 @@ func errorMessageToClientError(msg string) error {
 		return ErrUnsupportedOperation
 	case InvalidSyntaxMessasge:
 		return ErrInvalidSyntax
+	case DivisionByZeroMessage:
+		return ErrDivisionByZero
+	case OverflowMessage:
+		return ErrOverflow
+	case DomainMessage:
+		return ErrDomain
+	case UndefinedVariableMessage:
+		return ErrUndefinedVariable
+	case NoPreviousResultMessage:
+		return ErrNoPreviousResult
+	case DimensionMismatchMessage:
+		return ErrDimensionMismatch
+	case InvalidDateMessage:
+		return ErrInvalidDate
+	case UnknownTimeZoneMessage:
+		return ErrUnknownTimeZone
+	case UnsupportedLocaleMessage:
+		return ErrUnsupportedLocale
+	case UnclosedGroupMessage:
+		return ErrUnclosedGroup
+	case UnopenedGroupMessage:
+		return ErrUnopenedGroup
+	case EmptyGroupMessage:
+		return ErrEmptyGroup
 	default:
 		return NewClientError("unknown error response")
 	}
@@ -18,6 +18,7 @@ type StubHttpClient struct {
 	spyURL         string
 	spyContentType string
 	spyData        io.Reader
+	spySession     string
 
 	code     int
 	response interface{}
@@ -62,18 +63,33 @@ func (s *StubHttpClient) Get(url string) (*http.Response, error) {
 	return response, nil
 }
 
+func (s *StubHttpClient) Do(request *http.Request) (*http.Response, error) {
+	s.spySession = request.Header.Get(client.SessionHeader)
+	return s.Post(request.URL.String(), request.Header.Get("Content-Type"), request.Body)
+}
+
 func TestEvaluate(t *testing.T) {
 	t.Run("evaluates expression", func(t *testing.T) {
 		url := "example-url.com"
 
 		expression := "What is 5 plus 10?"
+		opts := client.EvaluateOptions{
+			Arithmetic: client.RationalArithmetic,
+			Order:      client.LeftToRightOrder,
+		}
 		wantExpressionRequest := client.ExpressionRequest{
 			Expression: expression,
+			Arithmetic: client.RationalArithmetic,
+			Order:      client.LeftToRightOrder,
 		}
 
-		wantResult := 15
+		wantResult := client.Result{
+			Value:      "15",
+			Arithmetic: client.RationalArithmetic,
+		}
 		evaluateResponse := client.EvaluateResponse{
-			Result: wantResult,
+			Result:     "15",
+			Arithmetic: client.RationalArithmetic,
 		}
 
 		httpClient := &StubHttpClient{
@@ -82,7 +98,7 @@ func TestEvaluate(t *testing.T) {
 		}
 		exprClient := client.NewExpressionHTTPClient(httpClient, url)
 
-		gotResult, _ := exprClient.Evaluate(expression)
+		gotResult, _ := exprClient.Evaluate(expression, opts)
 
 		assert.Equal(t, gotResult, wantResult)
 
@@ -94,6 +110,171 @@ func TestEvaluate(t *testing.T) {
 		assert.Equal(t, gotExpressionRequest, wantExpressionRequest)
 	})
 
+	t.Run("requests and decodes an answer", func(t *testing.T) {
+		url := "example-url.com"
+
+		expression := "What is 5 plus 3?"
+		wantExpressionRequest := client.ExpressionRequest{
+			Expression: expression,
+			Format:     client.SentenceFormat,
+		}
+		wantResult := client.Result{
+			Value:  "8",
+			Answer: "5 plus 3 is 8.",
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusOK,
+			response: client.EvaluateResponse{Result: "8", Answer: "5 plus 3 is 8."},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		gotResult, err := exprClient.Evaluate(expression, client.EvaluateOptions{Format: client.SentenceFormat})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotResult, wantResult)
+
+		var gotExpressionRequest client.ExpressionRequest
+		json.NewDecoder(httpClient.spyData).Decode(&gotExpressionRequest)
+
+		assert.Equal(t, gotExpressionRequest, wantExpressionRequest)
+	})
+
+	t.Run("requests and decodes a trace", func(t *testing.T) {
+		url := "example-url.com"
+
+		expression := "What is 2 plus 3?"
+		wantExpressionRequest := client.ExpressionRequest{
+			Expression: expression,
+			Trace:      true,
+		}
+		wantResult := client.Result{
+			Value: "5",
+			Trace: []client.Step{
+				{Text: "2 plus 3 = 5", Operator: "plus", Operands: []string{"2", "3"}, Result: "5", Offset: 10, Length: 4},
+			},
+		}
+
+		httpClient := &StubHttpClient{
+			code: http.StatusOK,
+			response: client.EvaluateResponse{Result: "5", Trace: []client.StepResponse{
+				{Text: "2 plus 3 = 5", Operator: "plus", Operands: []client.Number{"2", "3"}, Result: "5", Offset: 10, Length: 4},
+			}},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		gotResult, err := exprClient.Evaluate(expression, client.EvaluateOptions{Trace: true})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotResult, wantResult)
+
+		var gotExpressionRequest client.ExpressionRequest
+		json.NewDecoder(httpClient.spyData).Decode(&gotExpressionRequest)
+
+		assert.Equal(t, gotExpressionRequest, wantExpressionRequest)
+	})
+
+	t.Run("sends session id", func(t *testing.T) {
+		url := "example-url.com"
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusOK,
+			response: client.EvaluateResponse{Result: "16"},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, err := exprClient.Evaluate("What is the result multiplied by 2?", client.EvaluateOptions{Session: "abc"})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, httpClient.spySession, "abc")
+		assert.Equal(t, httpClient.spyContentType, "application/json")
+	})
+
+	t.Run("recognises no previous result error", func(t *testing.T) {
+		url := "example-url.com"
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: client.ErrorResponse{Error: client.NoPreviousResultMessage},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate("What is the result plus 1?", client.EvaluateOptions{})
+
+		assert.Equal(t, gotError, client.ErrNoPreviousResult)
+	})
+
+	t.Run("decodes non-numeric results", func(t *testing.T) {
+		url := "example-url.com"
+
+		wantResult := client.Result{
+			Value:      "7/2",
+			Arithmetic: client.RationalArithmetic,
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusOK,
+			response: map[string]string{"result": "7/2", "arithmetic": "rational"},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		gotResult, err := exprClient.Evaluate("What is 7 divided by 2?", client.EvaluateOptions{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotResult, wantResult)
+	})
+
+	t.Run("decodes boolean results", func(t *testing.T) {
+		url := "example-url.com"
+
+		wantResult := client.Result{
+			Value:      "false",
+			Type:       client.BooleanResult,
+			Arithmetic: client.IntegerArithmetic,
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusOK,
+			response: map[string]any{"result": false, "type": "boolean", "arithmetic": "integer"},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		gotResult, err := exprClient.Evaluate("Is 5 less than 3?", client.EvaluateOptions{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotResult, wantResult)
+	})
+
+	t.Run("decodes the results of several sentences", func(t *testing.T) {
+		url := "example-url.com"
+
+		response := map[string]any{"sentences": []any{
+			map[string]any{"sentence": "What is 1 plus 1?", "result": 2, "type": "number", "arithmetic": "integer"},
+			map[string]any{"sentence": "What is x?", "message": "undefined variable"},
+		}}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusOK,
+			response: response,
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		gotResult, err := exprClient.Evaluate("What is 1 plus 1? What is x?", client.EvaluateOptions{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, len(gotResult.Sentences), 2)
+		assert.Equal(t, gotResult.Sentences[0], client.SentenceResult{
+			Sentence: "What is 1 plus 1?",
+			Result: client.Result{
+				Value:      "2",
+				Type:       client.NumberResult,
+				Arithmetic: client.IntegerArithmetic,
+			},
+		})
+		assert.Equal(t, gotResult.Sentences[1].Sentence, "What is x?")
+		assert.ErrorIs(t, gotResult.Sentences[1].Err, client.ErrUndefinedVariable)
+	})
+
 	t.Run("parses and returns error on Status Bad Request", func(t *testing.T) {
 		url := "example-url.com"
 
@@ -110,13 +291,257 @@ func TestEvaluate(t *testing.T) {
 		}
 		exprClient := client.NewExpressionHTTPClient(httpClient, url)
 
-		_, gotError := exprClient.Evaluate(expression)
+		_, gotError := exprClient.Evaluate(expression, client.EvaluateOptions{})
 		assert.RequireNotNil(t, gotError)
 
 		assert.ErrorType[*client.ClientError](t, gotError)
 		assert.Equal(t, gotError, wantError)
 	})
 
+	t.Run("recognises overflow error", func(t *testing.T) {
+		url := "example-url.com"
+
+		expression := "What is 9999999999 multiplied by 9999999999?"
+
+		wantError := client.ErrOverflow
+		errorResponse := client.ErrorResponse{
+			Error: client.OverflowMessage,
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: errorResponse,
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate(expression, client.EvaluateOptions{})
+		assert.RequireNotNil(t, gotError)
+
+		assert.Equal(t, gotError, wantError)
+	})
+
+	t.Run("attaches diagnostic to recognised error", func(t *testing.T) {
+		url := "example-url.com"
+
+		expression := "What is 5 plus plus 3?"
+
+		wantDiagnostic := client.Diagnostic{
+			Offset:   15,
+			Length:   4,
+			Text:     "plus",
+			Expected: []string{"number"},
+		}
+		errorResponse := client.ErrorResponse{
+			Error: client.InvalidSyntaxMessasge,
+			Diagnostic: &client.DiagnosticResponse{
+				Offset:   15,
+				Length:   4,
+				Text:     "plus",
+				Expected: []string{"number"},
+			},
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: errorResponse,
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate(expression, client.EvaluateOptions{})
+		assert.RequireNotNil(t, gotError)
+		assert.ErrorIs(t, gotError, client.ErrInvalidSyntax)
+
+		var diagnosticErr *client.DiagnosticError
+		assert.Equal(t, errors.As(gotError, &diagnosticErr), true)
+		assert.Equal(t, diagnosticErr.Diagnostic, wantDiagnostic)
+	})
+
+	t.Run("decodes suggestions of a diagnostic", func(t *testing.T) {
+		url := "example-url.com"
+
+		errorResponse := client.ErrorResponse{
+			Error: client.UnsupportedOperationMessage,
+			Diagnostic: &client.DiagnosticResponse{
+				Offset:      10,
+				Length:      4,
+				Text:        "plsu",
+				Suggestions: []string{"plus"},
+			},
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: errorResponse,
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate("What is 5 plsu 3?", client.EvaluateOptions{})
+		assert.ErrorIs(t, gotError, client.ErrUnsupportedOperation)
+
+		var diagnosticErr *client.DiagnosticError
+		assert.Equal(t, errors.As(gotError, &diagnosticErr), true)
+		assert.Equal(t, diagnosticErr.Diagnostic.Suggestions, []string{"plus"})
+	})
+
+	t.Run("recognises domain error", func(t *testing.T) {
+		url := "example-url.com"
+
+		expression := "What is the square root of -4?"
+
+		wantError := client.ErrDomain
+		errorResponse := client.ErrorResponse{
+			Error: client.DomainMessage,
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: errorResponse,
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate(expression, client.EvaluateOptions{})
+		assert.RequireNotNil(t, gotError)
+
+		assert.Equal(t, gotError, wantError)
+	})
+
+	t.Run("recognises dimension mismatch error", func(t *testing.T) {
+		url := "example-url.com"
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: client.ErrorResponse{Error: client.DimensionMismatchMessage},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate("What is 5 meters plus 3 seconds?", client.EvaluateOptions{})
+
+		assert.Equal(t, gotError, client.ErrDimensionMismatch)
+	})
+
+	t.Run("recognises unknown time zone error", func(t *testing.T) {
+		url := "example-url.com"
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: client.ErrorResponse{Error: client.UnknownTimeZoneMessage},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate("What is 2024-03-01 plus 1 day?", client.EvaluateOptions{TimeZone: "Mars/Olympus"})
+
+		assert.Equal(t, gotError, client.ErrUnknownTimeZone)
+	})
+
+	t.Run("requests the locale of the expression", func(t *testing.T) {
+		url := "example-url.com"
+
+		expression := "Was ist 5 plus 3?"
+		wantExpressionRequest := client.ExpressionRequest{
+			Expression: expression,
+			Locale:     "de",
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusOK,
+			response: client.EvaluateResponse{Result: "8"},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		gotResult, err := exprClient.Evaluate(expression, client.EvaluateOptions{Locale: "de"})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotResult, client.Result{Value: "8"})
+
+		var gotExpressionRequest client.ExpressionRequest
+		json.NewDecoder(httpClient.spyData).Decode(&gotExpressionRequest)
+
+		assert.Equal(t, gotExpressionRequest, wantExpressionRequest)
+	})
+
+	t.Run("recognises unsupported locale error", func(t *testing.T) {
+		url := "example-url.com"
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: client.ErrorResponse{Error: client.UnsupportedLocaleMessage},
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate("What is 5 plus 3?", client.EvaluateOptions{Locale: "xx"})
+
+		assert.Equal(t, gotError, client.ErrUnsupportedLocale)
+	})
+
+	t.Run("recognises group errors as invalid syntax", func(t *testing.T) {
+		cases := []struct {
+			Message string
+			Want    error
+		}{
+			{client.UnclosedGroupMessage, client.ErrUnclosedGroup},
+			{client.UnopenedGroupMessage, client.ErrUnopenedGroup},
+			{client.EmptyGroupMessage, client.ErrEmptyGroup},
+		}
+
+		for _, test := range cases {
+			httpClient := &StubHttpClient{
+				code:     http.StatusBadRequest,
+				response: client.ErrorResponse{Error: test.Message},
+			}
+			exprClient := client.NewExpressionHTTPClient(httpClient, "example-url.com")
+
+			_, gotError := exprClient.Evaluate("What is (1 plus 2?", client.EvaluateOptions{})
+
+			assert.Equal(t, gotError, test.Want)
+			assert.Equal(t, gotError.Error(), test.Message)
+			assert.ErrorIs(t, gotError, client.ErrInvalidSyntax)
+		}
+	})
+
+	t.Run("recognises undefined variable error", func(t *testing.T) {
+		url := "example-url.com"
+
+		expression := "What is x plus 1?"
+
+		wantError := client.ErrUndefinedVariable
+		errorResponse := client.ErrorResponse{
+			Error: client.UndefinedVariableMessage,
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: errorResponse,
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate(expression, client.EvaluateOptions{})
+		assert.RequireNotNil(t, gotError)
+
+		assert.Equal(t, gotError, wantError)
+	})
+
+	t.Run("recognises division by zero error", func(t *testing.T) {
+		url := "example-url.com"
+
+		expression := "What is 5 divided by 0?"
+
+		wantError := client.ErrDivisionByZero
+		errorResponse := client.ErrorResponse{
+			Error: client.DivisionByZeroMessage,
+		}
+
+		httpClient := &StubHttpClient{
+			code:     http.StatusBadRequest,
+			response: errorResponse,
+		}
+		exprClient := client.NewExpressionHTTPClient(httpClient, url)
+
+		_, gotError := exprClient.Evaluate(expression, client.EvaluateOptions{})
+		assert.RequireNotNil(t, gotError)
+
+		assert.Equal(t, gotError, wantError)
+	})
+
 	t.Run("wraps error message in ClientError on Internal Server Error", func(t *testing.T) {
 		url := "example-url.com"
 
@@ -133,7 +558,7 @@ func TestEvaluate(t *testing.T) {
 		}
 		exprClient := client.NewExpressionHTTPClient(httpClient, url)
 
-		_, gotError := exprClient.Evaluate(expression)
+		_, gotError := exprClient.Evaluate(expression, client.EvaluateOptions{})
 		assert.RequireNotNil(t, gotError)
 
 		assert.ErrorType[*client.ClientError](t, gotError)
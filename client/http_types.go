@@ -1,32 +1,136 @@
 package client
 
+import (
+	"encoding/json"
+	"strconv"
+)
+
 const (
 	EvaluateURL         = "/evaluate"
 	ValidateURL         = "/validate"
 	ExpressionErrorsURL = "/errors"
 )
 
+// SessionHeader carries the id of the session whose variables and previous
+// result an evaluation can read.
+const SessionHeader = "X-Session-Id"
+
 const (
 	NonMathQuestionMessage      = "non-math question"
 	UnsupportedOperationMessage = "unsupported operation"
 	InvalidSyntaxMessasge       = "invalid syntax"
+	DivisionByZeroMessage       = "division by zero"
+	OverflowMessage             = "overflow"
+	DomainMessage               = "math domain error"
+	UndefinedVariableMessage    = "undefined variable"
+	NoPreviousResultMessage     = "no previous result"
+	DimensionMismatchMessage    = "dimension mismatch"
+	InvalidDateMessage          = "invalid date"
+	UnknownTimeZoneMessage      = "unknown time zone"
+	UnclosedGroupMessage        = "invalid syntax: unclosed group"
+	UnopenedGroupMessage        = "invalid syntax: closing an unopened group"
+	EmptyGroupMessage           = "invalid syntax: empty group"
+	UnsupportedLocaleMessage    = "unsupported locale"
 )
 
 type ErrorResponse struct {
-	Error string `json:"message"`
+	Error      string              `json:"message"`
+	Diagnostic *DiagnosticResponse `json:"diagnostic,omitempty"`
+}
+
+type DiagnosticResponse struct {
+	Offset      int      `json:"offset"`
+	Length      int      `json:"length"`
+	Text        string   `json:"text"`
+	Expected    []string `json:"expected,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 type ValidateResponse struct {
-	Valid  bool   `json:"valid"`
-	Reason string `json:"reason,omitempty"`
+	Valid      bool                `json:"valid"`
+	Reason     string              `json:"reason,omitempty"`
+	Diagnostic *DiagnosticResponse `json:"diagnostic,omitempty"`
 }
 
+// EvaluateResponse holds the result of an expression. An expression made of
+// several sentences is answered with Sentences instead.
 type EvaluateResponse struct {
-	Result int `json:"result"`
+	Result     Number             `json:"result"`
+	Type       string             `json:"type"`
+	Arithmetic string             `json:"arithmetic"`
+	Answer     string             `json:"answer,omitempty"`
+	Trace      []StepResponse     `json:"trace,omitempty"`
+	Sentences  []SentenceResponse `json:"sentences,omitempty"`
+}
+
+type StepResponse struct {
+	Text     string   `json:"text"`
+	Operator string   `json:"operator"`
+	Operands []Number `json:"operands"`
+	Result   Number   `json:"result"`
+	Offset   int      `json:"offset"`
+	Length   int      `json:"length"`
+}
+
+type SentenceResponse struct {
+	Sentence   string              `json:"sentence"`
+	Result     Number              `json:"result,omitempty"`
+	Type       string              `json:"type,omitempty"`
+	Arithmetic string              `json:"arithmetic,omitempty"`
+	Answer     string              `json:"answer,omitempty"`
+	Trace      []StepResponse      `json:"trace,omitempty"`
+	Error      string              `json:"message,omitempty"`
+	Diagnostic *DiagnosticResponse `json:"diagnostic,omitempty"`
 }
 
 type ExpressionRequest struct {
 	Expression string `json:"expression"`
+	Arithmetic string `json:"arithmetic,omitempty"`
+	Order      string `json:"order,omitempty"`
+	TimeZone   string `json:"timezone,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Trace      bool   `json:"trace,omitempty"`
+	Locale     string `json:"locale,omitempty"`
+}
+
+// Number holds the textual form of a result, which the server encodes as a
+// JSON number, as a JSON boolean for the answer to a yes/no question or, when
+// it is not a valid number literal, as a string.
+type Number string
+
+func (n Number) MarshalJSON() ([]byte, error) {
+	if n == "true" || n == "false" {
+		return []byte(n), nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal([]byte(n), &number); err == nil {
+		return []byte(n), nil
+	}
+
+	return json.Marshal(string(n))
+}
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*n = Number(str)
+		return nil
+	}
+
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		*n = Number(strconv.FormatBool(boolean))
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+
+	*n = Number(number)
+	return nil
 }
 
 type ExpressionErrorResponse struct {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,11 +13,26 @@ import (
 )
 
 func main() {
+	suggestionDistance := flag.Int("suggestion-distance", interp.DefaultSuggestionDistance, "largest edit distance at which a known word is suggested for an unknown one, 0 to turn suggestions off")
+	sessionTTL := flag.Duration("session-ttl", repo.DefaultSessionTTL, "how long a session is kept after it was last used")
+	flag.Parse()
+
 	exprErrorRepo := repo.NewInMemoryExprErrorRepository()
+	sessionRepo := repo.NewInMemorySessionRepository(*sessionTTL)
+
+	operators := interp.NewOperatorRegistry()
+	operators.SetSuggestionDistance(*suggestionDistance)
+	exprInterp := interp.NewInterpMW(operators.Lex, operators.Parse, operators.ParseLeftToRight, operators.Interpret)
 
-	exprInterp := interp.NewInterpMW(interp.Lex, interp.Parse, interp.Interpret)
+	for _, locale := range interp.Locales {
+		localised, err := operators.Localise(locale)
+		if err != nil {
+			log.Fatal(err)
+		}
+		exprInterp.AddLocale(locale.Tag, localised.Lex, localised.Parse, localised.ParseLeftToRight, localised.Interpret)
+	}
 
-	exprService := service.NewExpressionService(exprInterp, exprErrorRepo)
+	exprService := service.NewExpressionService(exprInterp, exprErrorRepo, sessionRepo)
 
 	exprHandler := handler.NewExpressionHandler(exprService)
 
@@ -2,15 +2,21 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/VitoNaychev/eval-web-service/service"
 )
 
 type ExpressionService interface {
-	Evaluate(string) (int, error)
-	Validate(string) (bool, error)
+	EvaluateSentences(string, service.EvaluateOptions) ([]service.SentenceResult, error)
+	Validate(string, service.ValidateOptions) (bool, error)
 	GetExpressionErrors() ([]service.ExpressionError, error)
+	GetVariables(string) (map[string]string, error)
+	ClearVariables(string) error
 }
 
 type ExpressionHandler struct {
@@ -23,36 +29,147 @@ func NewExpressionHandler(service ExpressionService) *ExpressionHandler {
 	}
 }
 
+// Evaluate evaluates the expression of the request. An expression made of a
+// single sentence is answered with an EvaluateResponse, or an ErrorResponse
+// when it fails. An expression made of several sentences is answered with a
+// SentencesResponse that holds the outcome of each of them.
 func (e *ExpressionHandler) Evaluate(w http.ResponseWriter, r *http.Request) {
 	var exprRequest ExpressionRequest
 	json.NewDecoder(r.Body).Decode(&exprRequest)
 
-	result, err := e.service.Evaluate(exprRequest.Expression)
+	arithmetic, err := arithmeticToServiceArithmetic(exprRequest.Arithmetic)
 	if err != nil {
 		writeJSONError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	evalResponse := EvaluateResponse{
-		Result: result,
+	order, err := orderToServiceOrder(exprRequest.Order)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	format := exprRequest.Format
+	if format == "" {
+		format = r.URL.Query().Get("format")
+	}
+	serviceFormat, err := formatToServiceFormat(format)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	evalOpts := service.EvaluateOptions{
+		Arithmetic: arithmetic,
+		Order:      order,
+		Locales:    requestLocales(r, exprRequest),
+		Session:    r.Header.Get(SessionHeader),
+		TimeZone:   exprRequest.TimeZone,
+		Format:     serviceFormat,
+		Trace:      exprRequest.Trace || r.URL.Query().Get("trace") == "true",
+	}
+
+	results, err := e.service.EvaluateSentences(exprRequest.Expression, evalOpts)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(results) == 1 {
+		if results[0].Err != nil {
+			writeJSONError(w, http.StatusBadRequest, results[0].Err)
+			return
+		}
+
+		result := results[0].Result
+		evalResponse := EvaluateResponse{
+			Result:     Number(result.Value),
+			Type:       serviceResultTypeToResultType(result.Type),
+			Arithmetic: serviceArithmeticToArithmetic(result.Arithmetic),
+			Answer:     result.Answer,
+			Trace:      stepsToStepResponses(result.Steps),
+		}
+		json.NewEncoder(w).Encode(evalResponse)
+		return
+	}
+
+	sentencesResponse := SentencesResponse{
+		Sentences: []SentenceResponse{},
+	}
+	for _, result := range results {
+		sentencesResponse.Sentences = append(sentencesResponse.Sentences, sentenceResultToSentenceResponse(result))
+	}
+	json.NewEncoder(w).Encode(sentencesResponse)
+}
+
+func sentenceResultToSentenceResponse(r service.SentenceResult) SentenceResponse {
+	if r.Err != nil {
+		return SentenceResponse{
+			Sentence:   r.Sentence,
+			Error:      r.Err.Error(),
+			Diagnostic: errorToDiagnosticResponse(r.Err),
+		}
+	}
+
+	return SentenceResponse{
+		Sentence:   r.Sentence,
+		Result:     Number(r.Result.Value),
+		Type:       serviceResultTypeToResultType(r.Result.Type),
+		Arithmetic: serviceArithmeticToArithmetic(r.Result.Arithmetic),
+		Answer:     r.Result.Answer,
+		Trace:      stepsToStepResponses(r.Result.Steps),
+	}
+}
+
+func stepsToStepResponses(steps []service.Step) []StepResponse {
+	if steps == nil {
+		return nil
 	}
-	json.NewEncoder(w).Encode(evalResponse)
+
+	responses := make([]StepResponse, len(steps))
+	for i, step := range steps {
+		operands := make([]Number, len(step.Operands))
+		for j, operand := range step.Operands {
+			operands[j] = Number(operand)
+		}
+
+		responses[i] = StepResponse{
+			Text:     step.Text,
+			Operator: step.Operator,
+			Operands: operands,
+			Result:   Number(step.Result),
+			Offset:   step.Offset,
+			Length:   step.Length,
+		}
+	}
+	return responses
 }
 
 func (e *ExpressionHandler) Validate(w http.ResponseWriter, r *http.Request) {
 	var exprRequest ExpressionRequest
 	json.NewDecoder(r.Body).Decode(&exprRequest)
 
-	isValid, err := e.service.Validate(exprRequest.Expression)
+	validateOpts := service.ValidateOptions{
+		Locales: requestLocales(r, exprRequest),
+	}
+
+	isValid, err := e.service.Validate(exprRequest.Expression, validateOpts)
+
+	diagnostic := errorToDiagnosticResponse(err)
 
 	var reason string
 	if err != nil {
 		reason = err.Error()
 	}
+	var diagnosticErr *service.DiagnosticError
+	if errors.As(err, &diagnosticErr) && diagnosticErr.Diagnostic.Hint != "" {
+		reason += ", " + diagnosticErr.Diagnostic.Hint
+	}
 
 	validateResponse := ValidateResponse{
-		Valid:  isValid,
-		Reason: reason,
+		Valid:      isValid,
+		Reason:     reason,
+		Diagnostic: diagnostic,
 	}
 	json.NewEncoder(w).Encode(validateResponse)
 }
@@ -72,6 +189,48 @@ func (e *ExpressionHandler) GetExpressionErrors(w http.ResponseWriter, r *http.R
 	json.NewEncoder(w).Encode(exprErrorsResponse)
 }
 
+// GetVariables lists the variables assigned in the session named by the
+// session header.
+func (e *ExpressionHandler) GetVariables(w http.ResponseWriter, r *http.Request) {
+	session := r.Header.Get(SessionHeader)
+	if session == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrMissingSession)
+		return
+	}
+
+	variables, err := e.service.GetVariables(session)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	variablesResponse := VariablesResponse{
+		Variables: map[string]Number{},
+	}
+	for name, value := range variables {
+		variablesResponse.Variables[name] = Number(value)
+	}
+	json.NewEncoder(w).Encode(variablesResponse)
+}
+
+// ClearVariables forgets the variables assigned in the session named by the
+// session header. The previous result of the session is kept.
+func (e *ExpressionHandler) ClearVariables(w http.ResponseWriter, r *http.Request) {
+	session := r.Header.Get(SessionHeader)
+	if session == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrMissingSession)
+		return
+	}
+
+	err := e.service.ClearVariables(session)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func exprErrorToExprErrorResponse(e service.ExpressionError) (ExpressionErrorResponse, error) {
 	endpoint, err := serviceMethodToEndpoint(e.Method)
 	if err != nil {
@@ -110,16 +269,177 @@ func serviceTypeToHandlerType(t service.ErrorType) (string, error) {
 		return UnsupportedOperandType, nil
 	case service.ErrorTypeInvalidSyntax:
 		return InvalidSyntaxType, nil
+	case service.ErrorTypeDivisionByZero:
+		return DivisionByZeroType, nil
+	case service.ErrorTypeOverflow:
+		return OverflowType, nil
+	case service.ErrorTypeDomain:
+		return DomainType, nil
+	case service.ErrorTypeUnsupportedLocale:
+		return UnsupportedLocaleType, nil
+	case service.ErrorTypeUndefinedVariable:
+		return UndefinedVariableType, nil
+	case service.ErrorTypeNoPreviousResult:
+		return NoPreviousResultType, nil
+	case service.ErrorTypeDimensionMismatch:
+		return DimensionMismatchType, nil
+	case service.ErrorTypeInvalidDate:
+		return InvalidDateType, nil
+	case service.ErrorTypeUnknownTimeZone:
+		return UnknownTimeZoneType, nil
 	default:
 		return "", ErrUnknownExpressionError
 	}
 }
 
+// requestLocales returns the languages the expression may be written in, in
+// order of preference. A locale named in the request must be supported,
+// while the languages of the Accept-Language header fall back to the default
+// one when none of them is.
+func requestLocales(r *http.Request, exprRequest ExpressionRequest) []string {
+	if exprRequest.Locale != "" {
+		return []string{exprRequest.Locale}
+	}
+
+	header := r.Header.Get("Accept-Language")
+	if header == "" {
+		return nil
+	}
+
+	return append(parseAcceptLanguage(header), "*")
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header
+// sorted by their quality value. Tags with a zero or malformed quality are
+// dropped.
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	languages := []language{}
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		languages = append(languages, language{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	tags := make([]string, len(languages))
+	for i, language := range languages {
+		tags[i] = language.tag
+	}
+	return tags
+}
+
+func arithmeticToServiceArithmetic(a string) (service.Arithmetic, error) {
+	switch a {
+	case "", IntegerArithmetic:
+		return service.ArithmeticInteger, nil
+	case FloatArithmetic:
+		return service.ArithmeticFloat, nil
+	case DecimalArithmetic:
+		return service.ArithmeticDecimal, nil
+	case RationalArithmetic:
+		return service.ArithmeticRational, nil
+	case BigIntegerArithmetic:
+		return service.ArithmeticBigInteger, nil
+	default:
+		return service.Arithmetic(-1), ErrUnknownArithmetic
+	}
+}
+
+func orderToServiceOrder(o string) (service.Order, error) {
+	switch o {
+	case "", PrecedenceOrder:
+		return service.OrderPrecedence, nil
+	case LeftToRightOrder:
+		return service.OrderLeftToRight, nil
+	default:
+		return service.Order(-1), ErrUnknownOrder
+	}
+}
+
+func serviceArithmeticToArithmetic(a service.Arithmetic) string {
+	switch a {
+	case service.ArithmeticFloat:
+		return FloatArithmetic
+	case service.ArithmeticDecimal:
+		return DecimalArithmetic
+	case service.ArithmeticRational:
+		return RationalArithmetic
+	case service.ArithmeticBigInteger:
+		return BigIntegerArithmetic
+	default:
+		return IntegerArithmetic
+	}
+}
+
+func formatToServiceFormat(f string) (service.Format, error) {
+	switch f {
+	case "", NumberFormat:
+		return service.FormatNumber, nil
+	case SentenceFormat:
+		return service.FormatSentence, nil
+	case WordsFormat:
+		return service.FormatWords, nil
+	default:
+		return service.Format(-1), ErrUnknownFormat
+	}
+}
+
+func serviceResultTypeToResultType(t service.ResultType) string {
+	switch t {
+	case service.ResultBoolean:
+		return BooleanResult
+	case service.ResultDate:
+		return DateResult
+	default:
+		return NumberResult
+	}
+}
+
 func writeJSONError(w http.ResponseWriter, statusCode int, err error) {
 	errorResponse := ErrorResponse{
-		Error: err.Error(),
+		Error:      err.Error(),
+		Diagnostic: errorToDiagnosticResponse(err),
 	}
 
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(errorResponse)
 }
+
+func errorToDiagnosticResponse(err error) *DiagnosticResponse {
+	var diagnosticErr *service.DiagnosticError
+	if !errors.As(err, &diagnosticErr) {
+		return nil
+	}
+
+	diagnostic := diagnosticErr.Diagnostic
+	return &DiagnosticResponse{
+		Offset:      diagnostic.Offset,
+		Length:      diagnostic.Length,
+		Text:        diagnostic.Text,
+		Expected:    diagnostic.Expected,
+		Suggestions: diagnostic.Suggestions,
+	}
+}
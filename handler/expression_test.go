@@ -6,42 +6,622 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/VitoNaychev/eval-web-service/handler"
+	"github.com/VitoNaychev/eval-web-service/interp"
+	"github.com/VitoNaychev/eval-web-service/repo"
 	"github.com/VitoNaychev/eval-web-service/service"
 	"github.com/VitoNaychev/eval-web-service/testutil/assert"
 )
 
 type StubExpressionService struct {
-	result     int
+	result     service.Result
+	sentences  []service.SentenceResult
 	isValid    bool
 	exprErrors []service.ExpressionError
+	variables  map[string]string
 	err        error
+
+	spyEvaluateOpts service.EvaluateOptions
+	spyValidateOpts service.ValidateOptions
+	spySession      string
+}
+
+func (s *StubExpressionService) EvaluateSentences(expression string, opts service.EvaluateOptions) ([]service.SentenceResult, error) {
+	s.spyEvaluateOpts = opts
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.sentences != nil {
+		return s.sentences, nil
+	}
+	return []service.SentenceResult{{Sentence: expression, Result: s.result}}, nil
 }
 
-func (s *StubExpressionService) Evaluate(expression string) (int, error) {
-	return s.result, s.err
-}
+func (s *StubExpressionService) Validate(expression string, opts service.ValidateOptions) (bool, error) {
+	s.spyValidateOpts = opts
+	return s.isValid, s.err
+}
+
+func (s *StubExpressionService) GetExpressionErrors() ([]service.ExpressionError, error) {
+	return s.exprErrors, s.err
+}
+
+func (s *StubExpressionService) GetVariables(session string) (map[string]string, error) {
+	s.spySession = session
+	return s.variables, s.err
+}
+
+func (s *StubExpressionService) ClearVariables(session string) error {
+	s.spySession = session
+	return s.err
+}
+
+// newExpressionHandler returns a handler backed by the real interpreter and
+// in-memory repositories, for the tests that depend on how an expression is
+// read rather than on what the service returns.
+func newExpressionHandler() *handler.ExpressionHandler {
+	interpMW := interp.NewInterpMW(interp.Lex, interp.Parse, interp.ParseLeftToRight, interp.Interpret)
+	for _, locale := range interp.Locales {
+		localised, _ := interp.NewOperatorRegistry().Localise(locale)
+		interpMW.AddLocale(locale.Tag, localised.Lex, localised.Parse, localised.ParseLeftToRight, localised.Interpret)
+	}
+	exprService := service.NewExpressionService(interpMW, repo.NewInMemoryExprErrorRepository(), repo.NewInMemorySessionRepository(repo.DefaultSessionTTL))
+	return handler.NewExpressionHandler(exprService)
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Run("evaluates expression and returns EvaluateResponse", func(t *testing.T) {
+		expression := "What is 5 plus 3?"
+		result := service.Result{
+			Value:      "8",
+			Arithmetic: service.ArithmeticInteger,
+		}
+
+		evalRequest := handler.ExpressionRequest{
+			Expression: expression,
+		}
+		wantResponse := handler.EvaluateResponse{
+			Result:     "8",
+			Type:       handler.NumberResult,
+			Arithmetic: handler.IntegerArithmetic,
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			result: result,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		var gotResponse handler.EvaluateResponse
+		json.NewDecoder(response.Body).Decode(&gotResponse)
+
+		assert.Equal(t, gotResponse, wantResponse)
+	})
+
+	t.Run("evaluates expression with requested arithmetic", func(t *testing.T) {
+		expression := "What is 7 divided by 2?"
+		result := service.Result{
+			Value:      "7/2",
+			Arithmetic: service.ArithmeticRational,
+		}
+
+		evalRequest := handler.ExpressionRequest{
+			Expression: expression,
+			Arithmetic: handler.RationalArithmetic,
+		}
+		wantOpts := service.EvaluateOptions{
+			Arithmetic: service.ArithmeticRational,
+		}
+		wantBody := `{"result":"7/2","type":"number","arithmetic":"rational"}` + "\n"
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			result: result,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+		assert.Equal(t, response.Body.String(), wantBody)
+	})
+
+	t.Run("encodes numeric results as JSON numbers", func(t *testing.T) {
+		result := service.Result{
+			Value:      "3.5",
+			Arithmetic: service.ArithmeticDecimal,
+		}
+		wantBody := `{"result":3.5,"type":"number","arithmetic":"decimal"}` + "\n"
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(handler.ExpressionRequest{})
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			result: result,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, response.Body.String(), wantBody)
+	})
+
+	t.Run("encodes boolean results as JSON booleans", func(t *testing.T) {
+		result := service.Result{
+			Value: "true",
+			Type:  service.ResultBoolean,
+		}
+		wantBody := `{"result":true,"type":"boolean","arithmetic":"integer"}` + "\n"
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(handler.ExpressionRequest{Expression: "Is 5 greater than 3?"})
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			result: result,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, response.Body.String(), wantBody)
+	})
+
+	t.Run("encodes date results as JSON strings", func(t *testing.T) {
+		result := service.Result{
+			Value: "2024-04-15",
+			Type:  service.ResultDate,
+		}
+		wantBody := `{"result":"2024-04-15","type":"date","arithmetic":"integer"}` + "\n"
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(handler.ExpressionRequest{Expression: "What is 2024-03-01 plus 45 days?"})
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			result: result,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, response.Body.String(), wantBody)
+	})
+
+	t.Run("returns Status Bad Request on unknown arithmetic", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 5 plus 3?",
+			Arithmetic: "roman",
+		}
+		wantErrorResponse := handler.ErrorResponse{
+			Error: handler.ErrUnknownArithmetic.Error(),
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusBadRequest)
+
+		var gotErrorResponse handler.ErrorResponse
+		json.NewDecoder(response.Body).Decode(&gotErrorResponse)
+
+		assert.Equal(t, gotErrorResponse, wantErrorResponse)
+	})
+
+	t.Run("passes left to right order to service", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 2 plus 3 multiplied by 4?",
+			Order:      handler.LeftToRightOrder,
+		}
+		wantOpts := service.EvaluateOptions{
+			Order: service.OrderLeftToRight,
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+	})
+
+	t.Run("returns Status Bad Request on unknown order", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 5 plus 3?",
+			Order:      "right-to-left",
+		}
+		wantErrorResponse := handler.ErrorResponse{
+			Error: handler.ErrUnknownOrder.Error(),
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusBadRequest)
+
+		var gotErrorResponse handler.ErrorResponse
+		json.NewDecoder(response.Body).Decode(&gotErrorResponse)
+
+		assert.Equal(t, gotErrorResponse, wantErrorResponse)
+	})
+
+	t.Run("reutrns Status Bad Request and ErrorResponse on invalid expression", func(t *testing.T) {
+		expression := "What is 5 plus 3?"
+		errorMessage := "handler error"
+
+		evalRequest := handler.ExpressionRequest{
+			Expression: expression,
+		}
+		wantErrorResponse := handler.ErrorResponse{
+			Error: errorMessage,
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			err: errors.New(errorMessage),
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusBadRequest)
+
+		var gotErrorResponse handler.ErrorResponse
+		json.NewDecoder(response.Body).Decode(&gotErrorResponse)
+
+		assert.Equal(t, gotErrorResponse, wantErrorResponse)
+	})
+
+	t.Run("includes diagnostic in ErrorResponse", func(t *testing.T) {
+		expression := "What is 5 plus plus 3?"
+		err := service.NewDiagnosticError(service.ErrInvalidSyntax, service.Diagnostic{
+			Offset:   15,
+			Length:   4,
+			Text:     "plus",
+			Expected: []string{"number", "prefix operand", "open group"},
+		})
+
+		evalRequest := handler.ExpressionRequest{
+			Expression: expression,
+		}
+		wantBody := `{"message":"invalid syntax","diagnostic":{"offset":15,"length":4,"text":"plus",` +
+			`"expected":["number","prefix operand","open group"]}}` + "\n"
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			err: err,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusBadRequest)
+
+		assert.Equal(t, response.Body.String(), wantBody)
+	})
+
+	t.Run("passes requested locale to service", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "Was ist 5 plus 3?",
+			Locale:     "de",
+		}
+		wantOpts := service.EvaluateOptions{
+			Locales: []string{"de"},
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		request.Header.Set("Accept-Language", "bg")
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+	})
+
+	t.Run("passes Accept-Language preferences to service", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "Колко е 5 плюс 3?",
+		}
+		wantOpts := service.EvaluateOptions{
+			Locales: []string{"bg-BG", "de", "en", "*"},
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		request.Header.Set("Accept-Language", "en;q=0.5, fr;q=0, bg-BG, de;q=0.8")
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+	})
+
+	t.Run("passes session id to service", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is x multiplied by 3?",
+		}
+		wantOpts := service.EvaluateOptions{
+			Session: "abc",
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		request.Header.Set(handler.SessionHeader, "abc")
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+	})
+
+	t.Run("passes requested time zone to service", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 2024-03-01 plus 10 hours?",
+			TimeZone:   "Europe/Sofia",
+		}
+		wantOpts := service.EvaluateOptions{
+			TimeZone: "Europe/Sofia",
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+	})
+
+	t.Run("returns unsupported locale error", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "Quanto fa 5 più 3?",
+			Locale:     "it",
+		}
+		wantResponse := handler.ErrorResponse{
+			Error: service.ErrUnsupportedLocale.Error(),
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			err: service.ErrUnsupportedLocale,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusBadRequest)
+
+		var gotResponse handler.ErrorResponse
+		json.NewDecoder(response.Body).Decode(&gotResponse)
+
+		assert.Equal(t, gotResponse, wantResponse)
+	})
+
+	t.Run("answers with a sentence in the requested format", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 5 plus 3?",
+			Format:     handler.WordsFormat,
+		}
+		wantOpts := service.EvaluateOptions{
+			Format: service.FormatWords,
+		}
+		wantBody := `{"result":8,"type":"number","arithmetic":"integer","answer":"Five plus three is eight."}` + "\n"
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			result: service.Result{Value: "8", Answer: "Five plus three is eight."},
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+		assert.Equal(t, response.Body.String(), wantBody)
+	})
+
+	t.Run("reads format from the query", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 5 plus 3?",
+		}
+		wantOpts := service.EvaluateOptions{
+			Format: service.FormatSentence,
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/?format=sentence", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+	})
+
+	t.Run("returns Status Bad Request on unknown format", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 5 plus 3?",
+			Format:     "poem",
+		}
+		wantErrorResponse := handler.ErrorResponse{
+			Error: handler.ErrUnknownFormat.Error(),
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusBadRequest)
+
+		var gotErrorResponse handler.ErrorResponse
+		json.NewDecoder(response.Body).Decode(&gotErrorResponse)
+
+		assert.Equal(t, gotErrorResponse, wantErrorResponse)
+	})
+
+	t.Run("adds the trace of the evaluation when asked for", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 2 plus 3?",
+			Trace:      true,
+		}
+		wantOpts := service.EvaluateOptions{
+			Trace: true,
+		}
+		wantBody := `{"result":5,"type":"number","arithmetic":"integer","trace":[{"text":"2 plus 3 = 5","operator":"plus","operands":[2,3],"result":5,"offset":10,"length":4}]}` + "\n"
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			result: service.Result{Value: "5", Steps: []service.Step{
+				{Text: "2 plus 3 = 5", Operator: "plus", Operands: []string{"2", "3"}, Result: "5", Offset: 10, Length: 4},
+			}},
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+		assert.Equal(t, response.Body.String(), wantBody)
+	})
 
-func (s *StubExpressionService) Validate(expression string) (bool, error) {
-	return s.isValid, s.err
-}
+	t.Run("reads trace from the query", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 2 plus 3?",
+		}
+		wantOpts := service.EvaluateOptions{
+			Trace: true,
+		}
 
-func (s *StubExpressionService) GetExpressionErrors() ([]service.ExpressionError, error) {
-	return s.exprErrors, s.err
-}
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
 
-func TestEvaluate(t *testing.T) {
-	t.Run("evaluates expression and returns EvaluateResponse", func(t *testing.T) {
-		expression := "What is 5 plus 3?"
-		result := 8
+		request, _ := http.NewRequest(http.MethodGet, "/?trace=true", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyEvaluateOpts, wantOpts)
+	})
+
+	t.Run("returns ErrorResponse for a single failing sentence", func(t *testing.T) {
+		expression := "What is 5 divided by 0?"
 
 		evalRequest := handler.ExpressionRequest{
 			Expression: expression,
 		}
-		wantResponse := handler.EvaluateResponse{
-			Result: result,
+		wantResponse := handler.ErrorResponse{
+			Error: service.ErrDivisionByZero.Error(),
 		}
 
 		body := bytes.NewBuffer([]byte{})
@@ -51,29 +631,30 @@ func TestEvaluate(t *testing.T) {
 		response := httptest.NewRecorder()
 
 		exprService := &StubExpressionService{
-			result: result,
+			sentences: []service.SentenceResult{
+				{Sentence: expression, Err: service.ErrDivisionByZero},
+			},
 		}
 		exprHandler := handler.NewExpressionHandler(exprService)
 
 		exprHandler.Evaluate(response, request)
-		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Code, http.StatusBadRequest)
 
-		var gotResponse handler.EvaluateResponse
+		var gotResponse handler.ErrorResponse
 		json.NewDecoder(response.Body).Decode(&gotResponse)
 
 		assert.Equal(t, gotResponse, wantResponse)
 	})
 
-	t.Run("reutrns Status Bad Request and ErrorResponse on invalid expression", func(t *testing.T) {
-		expression := "What is 5 plus 3?"
-		errorMessage := "handler error"
-
+	t.Run("returns SentencesResponse for several sentences", func(t *testing.T) {
 		evalRequest := handler.ExpressionRequest{
-			Expression: expression,
-		}
-		wantErrorResponse := handler.ErrorResponse{
-			Error: errorMessage,
+			Expression: "What is 1 plus 1? What is 2 plus? Is 2 greater than 1?",
 		}
+		wantBody := `{"sentences":[` +
+			`{"sentence":"What is 1 plus 1?","result":2,"type":"number","arithmetic":"integer"},` +
+			`{"sentence":"What is 2 plus?","message":"invalid syntax","diagnostic":{"offset":32,"length":1,"text":"?","expected":["number"]}},` +
+			`{"sentence":"Is 2 greater than 1?","result":true,"type":"boolean","arithmetic":"integer"}` +
+			`]}` + "\n"
 
 		body := bytes.NewBuffer([]byte{})
 		json.NewEncoder(body).Encode(evalRequest)
@@ -82,22 +663,152 @@ func TestEvaluate(t *testing.T) {
 		response := httptest.NewRecorder()
 
 		exprService := &StubExpressionService{
-			err: errors.New(errorMessage),
+			sentences: []service.SentenceResult{
+				{Sentence: "What is 1 plus 1?", Result: service.Result{Value: "2"}},
+				{Sentence: "What is 2 plus?", Err: service.NewDiagnosticError(service.ErrInvalidSyntax, service.Diagnostic{
+					Offset:   32,
+					Length:   1,
+					Text:     "?",
+					Expected: []string{"number"},
+				})},
+				{Sentence: "Is 2 greater than 1?", Result: service.Result{Value: "true", Type: service.ResultBoolean}},
+			},
 		}
 		exprHandler := handler.NewExpressionHandler(exprService)
 
 		exprHandler.Evaluate(response, request)
-		assert.Equal(t, response.Code, http.StatusBadRequest)
+		assert.Equal(t, response.Code, http.StatusOK)
 
-		var gotErrorResponse handler.ErrorResponse
-		json.NewDecoder(response.Body).Decode(&gotErrorResponse)
+		assert.Equal(t, response.Body.String(), wantBody)
+	})
+	t.Run("reports a word that can't be read in its own sentence", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "What is 1 plus 1? Who are you?",
+		}
+		wantResponse := handler.SentencesResponse{
+			Sentences: []handler.SentenceResponse{
+				{Sentence: "What is 1 plus 1?", Result: "2", Type: handler.NumberResult, Arithmetic: handler.IntegerArithmetic},
+				{Sentence: "Who are you?", Error: service.ErrNonMathQuestion.Error(), Diagnostic: &handler.DiagnosticResponse{
+					Offset:      18,
+					Length:      3,
+					Text:        "Who",
+					Expected:    []string{"question"},
+					Suggestions: []string{"how", "the", "to", "two", "what"},
+				}},
+			},
+		}
 
-		assert.Equal(t, gotErrorResponse, wantErrorResponse)
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprHandler := newExpressionHandler()
+
+		exprHandler.Evaluate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		var gotResponse handler.SentencesResponse
+		json.NewDecoder(response.Body).Decode(&gotResponse)
+
+		assert.Equal(t, gotResponse, wantResponse)
+	})
+
+	t.Run("reports the kind of a group error", func(t *testing.T) {
+		cases := []struct {
+			Name        string
+			Expression  string
+			WantMessage string
+		}{
+			{"unclosed group", "What is (1 plus 2?", "invalid syntax: unclosed group"},
+			{"unopened group", "What is 1 plus 2)?", "invalid syntax: closing an unopened group"},
+			{"empty group", "What is () plus 2?", "invalid syntax: empty group"},
+		}
+
+		for _, test := range cases {
+			t.Run(test.Name, func(t *testing.T) {
+				body := bytes.NewBuffer([]byte{})
+				json.NewEncoder(body).Encode(handler.ExpressionRequest{Expression: test.Expression})
+
+				request, _ := http.NewRequest(http.MethodGet, "/", body)
+				response := httptest.NewRecorder()
+
+				exprHandler := newExpressionHandler()
+
+				exprHandler.Evaluate(response, request)
+				assert.Equal(t, response.Code, http.StatusBadRequest)
+
+				var gotResponse handler.ErrorResponse
+				json.NewDecoder(response.Body).Decode(&gotResponse)
+
+				assert.Equal(t, gotResponse.Error, test.WantMessage)
+			})
+		}
+	})
+
+	t.Run("answers only expressions read in English", func(t *testing.T) {
+		cases := []struct {
+			Name       string
+			Request    handler.ExpressionRequest
+			WantStatus int
+			WantAnswer string
+		}{
+			{"english", handler.ExpressionRequest{Expression: "What is 2 multiplied by 3?", Locale: "en", Format: handler.SentenceFormat}, http.StatusOK, "2 multiplied by 3 is 6."},
+			{"german", handler.ExpressionRequest{Expression: "Was ist 2 mal 3?", Locale: "de", Format: handler.SentenceFormat}, http.StatusBadRequest, ""},
+			{"german without an answer", handler.ExpressionRequest{Expression: "Was ist 2 mal 3?", Locale: "de"}, http.StatusOK, ""},
+		}
+
+		for _, test := range cases {
+			t.Run(test.Name, func(t *testing.T) {
+				body := bytes.NewBuffer([]byte{})
+				json.NewEncoder(body).Encode(test.Request)
+
+				request, _ := http.NewRequest(http.MethodGet, "/", body)
+				response := httptest.NewRecorder()
+
+				exprHandler := newExpressionHandler()
+
+				exprHandler.Evaluate(response, request)
+				assert.Equal(t, response.Code, test.WantStatus)
+
+				var gotResponse handler.EvaluateResponse
+				json.NewDecoder(response.Body).Decode(&gotResponse)
+
+				assert.Equal(t, gotResponse.Answer, test.WantAnswer)
+			})
+		}
 	})
 }
 
 func TestValidate(t *testing.T) {
 
+	t.Run("passes requested locale to service", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: "Was ist 5 plus 3?",
+			Locale:     "de",
+		}
+		wantOpts := service.ValidateOptions{
+			Locales: []string{"de"},
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			isValid: true,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Validate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		assert.Equal(t, exprService.spyValidateOpts, wantOpts)
+	})
+
 	t.Run("sets Valid field to true on valid expression", func(t *testing.T) {
 		expression := "What is 5 plus 3?"
 		wantIsValid := true
@@ -160,6 +871,188 @@ func TestValidate(t *testing.T) {
 
 		assert.Equal(t, gotResponse, wantResponse)
 	})
+
+	t.Run("sets Diagnostic field on invalid expression", func(t *testing.T) {
+		expression := "What is 5 plus plus 3?"
+		err := service.NewDiagnosticError(service.ErrInvalidSyntax, service.Diagnostic{
+			Offset:   15,
+			Length:   4,
+			Text:     "plus",
+			Expected: []string{"number"},
+		})
+
+		evalRequest := handler.ExpressionRequest{
+			Expression: expression,
+		}
+		wantResponse := handler.ValidateResponse{
+			Valid:  false,
+			Reason: "invalid syntax",
+			Diagnostic: &handler.DiagnosticResponse{
+				Offset:   15,
+				Length:   4,
+				Text:     "plus",
+				Expected: []string{"number"},
+			},
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			err: err,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Validate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		var gotResponse handler.ValidateResponse
+		json.NewDecoder(response.Body).Decode(&gotResponse)
+
+		assert.Equal(t, gotResponse, wantResponse)
+	})
+
+	t.Run("adds the hint of the diagnostic to Reason", func(t *testing.T) {
+		expression := "What is 5 plsu 3?"
+		err := service.NewDiagnosticError(service.ErrUnsupportedOperation, service.Diagnostic{
+			Offset:      10,
+			Length:      4,
+			Text:        "plsu",
+			Suggestions: []string{"plus"},
+			Hint:        "did you mean 'plus'?",
+		})
+
+		evalRequest := handler.ExpressionRequest{
+			Expression: expression,
+		}
+		wantResponse := handler.ValidateResponse{
+			Valid:  false,
+			Reason: "unsupported operation, did you mean 'plus'?",
+			Diagnostic: &handler.DiagnosticResponse{
+				Offset:      10,
+				Length:      4,
+				Text:        "plsu",
+				Suggestions: []string{"plus"},
+			},
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			err: err,
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.Validate(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		var gotResponse handler.ValidateResponse
+		json.NewDecoder(response.Body).Decode(&gotResponse)
+
+		assert.Equal(t, gotResponse, wantResponse)
+	})
+
+	t.Run("validates each sentence of an expression", func(t *testing.T) {
+		cases := []struct {
+			Name       string
+			Expression string
+			Want       handler.ValidateResponse
+		}{
+			{
+				"valid sentences",
+				"What is 1 plus 1? What is 2 plus 2?",
+				handler.ValidateResponse{Valid: true},
+			},
+			{
+				"unclosed group",
+				"What is 1 plus 1? What is (2 plus 2?",
+				handler.ValidateResponse{
+					Reason: "invalid syntax: unclosed group",
+					Diagnostic: &handler.DiagnosticResponse{
+						Offset:   35,
+						Length:   1,
+						Text:     "?",
+						Expected: []string{"operand", "unit", "close group"},
+					},
+				},
+			},
+			{
+				"invalid second sentence",
+				"What is 1 plus 1? What is 2 plux 2?",
+				handler.ValidateResponse{
+					Reason: "unsupported operation, did you mean 'plus'?",
+					Diagnostic: &handler.DiagnosticResponse{
+						Offset:      28,
+						Length:      4,
+						Text:        "plux",
+						Suggestions: []string{"plus"},
+					},
+				},
+			},
+		}
+
+		for _, test := range cases {
+			t.Run(test.Name, func(t *testing.T) {
+				evalRequest := handler.ExpressionRequest{
+					Expression: test.Expression,
+				}
+
+				body := bytes.NewBuffer([]byte{})
+				json.NewEncoder(body).Encode(evalRequest)
+
+				request, _ := http.NewRequest(http.MethodGet, "/", body)
+				response := httptest.NewRecorder()
+
+				exprHandler := newExpressionHandler()
+
+				exprHandler.Validate(response, request)
+				assert.Equal(t, response.Code, http.StatusOK)
+
+				var gotResponse handler.ValidateResponse
+				json.NewDecoder(response.Body).Decode(&gotResponse)
+
+				assert.Equal(t, gotResponse, test.Want)
+			})
+		}
+	})
+
+	t.Run("validates an expression of many sentences", func(t *testing.T) {
+		evalRequest := handler.ExpressionRequest{
+			Expression: strings.Repeat("What is 1 plus 1? ", 5000),
+		}
+
+		body := bytes.NewBuffer([]byte{})
+		json.NewEncoder(body).Encode(evalRequest)
+
+		request, _ := http.NewRequest(http.MethodGet, "/", body)
+		response := httptest.NewRecorder()
+
+		exprHandler := newExpressionHandler()
+
+		done := make(chan struct{})
+		go func() {
+			exprHandler.Validate(response, request)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("validation took longer than 2s")
+		}
+
+		var gotResponse handler.ValidateResponse
+		json.NewDecoder(response.Body).Decode(&gotResponse)
+
+		assert.Equal(t, gotResponse, handler.ValidateResponse{Valid: true})
+	})
 }
 
 func TestGetErrors(t *testing.T) {
@@ -196,6 +1089,39 @@ func TestGetErrors(t *testing.T) {
 		assert.Equal(t, gotResponse, wantResponse)
 	})
 
+	t.Run("maps division by zero errors to their type", func(t *testing.T) {
+		exprError := service.ExpressionError{
+			Expression: "What is 5 divided by 0?",
+			Method:     service.MethodEvaluate,
+			Frequency:  1,
+			Type:       service.ErrorTypeDivisionByZero,
+		}
+		wantResponse := []handler.ExpressionErrorResponse{
+			{
+				Expression: exprError.Expression,
+				Endpoint:   handler.EvaluateEndpoint,
+				Frequency:  exprError.Frequency,
+				Type:       handler.DivisionByZeroType,
+			},
+		}
+
+		request, _ := http.NewRequest(http.MethodGet, "/", nil)
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			exprErrors: []service.ExpressionError{exprError},
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.GetExpressionErrors(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		var gotResponse []handler.ExpressionErrorResponse
+		json.NewDecoder(response.Body).Decode(&gotResponse)
+
+		assert.Equal(t, gotResponse, wantResponse)
+	})
+
 	t.Run("returns Internal Server Error on unknown method type from service", func(t *testing.T) {
 		exprError := service.ExpressionError{
 			Expression: "example expression",
@@ -252,3 +1178,67 @@ func TestGetErrors(t *testing.T) {
 		assert.Equal(t, gotResponse, wantResponse)
 	})
 }
+
+func TestVariables(t *testing.T) {
+	t.Run("lists the variables of the session", func(t *testing.T) {
+		wantResponse := handler.VariablesResponse{
+			Variables: map[string]handler.Number{"x": "5", "y": "7/2"},
+		}
+
+		request, _ := http.NewRequest(http.MethodGet, handler.VariablesEndpoint, nil)
+		request.Header.Set(handler.SessionHeader, "abc")
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{
+			variables: map[string]string{"x": "5", "y": "7/2"},
+		}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.GetVariables(response, request)
+		assert.Equal(t, response.Code, http.StatusOK)
+
+		var gotResponse handler.VariablesResponse
+		json.NewDecoder(response.Body).Decode(&gotResponse)
+
+		assert.Equal(t, exprService.spySession, "abc")
+		assert.Equal(t, gotResponse, wantResponse)
+	})
+
+	t.Run("clears the variables of the session", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodDelete, handler.VariablesEndpoint, nil)
+		request.Header.Set(handler.SessionHeader, "abc")
+		response := httptest.NewRecorder()
+
+		exprService := &StubExpressionService{}
+		exprHandler := handler.NewExpressionHandler(exprService)
+
+		exprHandler.ClearVariables(response, request)
+		assert.Equal(t, response.Code, http.StatusNoContent)
+
+		assert.Equal(t, exprService.spySession, "abc")
+	})
+
+	t.Run("returns Status Bad Request without a session id", func(t *testing.T) {
+		wantResponse := handler.ErrorResponse{
+			Error: handler.ErrMissingSession.Error(),
+		}
+
+		for _, handle := range []func(*handler.ExpressionHandler, http.ResponseWriter, *http.Request){
+			(*handler.ExpressionHandler).GetVariables,
+			(*handler.ExpressionHandler).ClearVariables,
+		} {
+			request, _ := http.NewRequest(http.MethodGet, handler.VariablesEndpoint, nil)
+			response := httptest.NewRecorder()
+
+			exprHandler := handler.NewExpressionHandler(&StubExpressionService{})
+
+			handle(exprHandler, response, request)
+			assert.Equal(t, response.Code, http.StatusBadRequest)
+
+			var gotResponse handler.ErrorResponse
+			json.NewDecoder(response.Body).Decode(&gotResponse)
+
+			assert.Equal(t, gotResponse, wantResponse)
+		}
+	})
+}
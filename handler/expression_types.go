@@ -1,20 +1,84 @@
 package handler
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
 
 var (
 	ErrUnknownMethod          = errors.New("unknwon method type")
 	ErrUnknownExpressionError = errors.New("unknwon expression error type")
+	ErrUnknownArithmetic      = errors.New("unknown arithmetic")
+	ErrUnknownFormat          = errors.New("unknown format")
+	ErrUnknownOrder           = errors.New("unknown order")
+	ErrMissingSession         = errors.New("missing session id")
+)
+
+// SessionHeader carries the id of the session that evaluations share
+// variables in.
+const SessionHeader = "X-Session-Id"
+
+const (
+	IntegerArithmetic    = "integer"
+	FloatArithmetic      = "float"
+	DecimalArithmetic    = "decimal"
+	RationalArithmetic   = "rational"
+	BigIntegerArithmetic = "big-integer"
+)
+
+// The orders operations can be applied in. PrecedenceOrder applies
+// "multiplied by" before "plus", while LeftToRightOrder keeps the original
+// semantics and applies them strictly left to right.
+const (
+	PrecedenceOrder  = "precedence"
+	LeftToRightOrder = "left-to-right"
+)
+
+const (
+	NumberResult  = "number"
+	BooleanResult = "boolean"
+	DateResult    = "date"
+)
+
+// The formats of an evaluation. NumberFormat only gives the result, while
+// SentenceFormat and WordsFormat also answer with a sentence, the latter
+// spelling out its numbers.
+const (
+	NumberFormat   = "number"
+	SentenceFormat = "sentence"
+	WordsFormat    = "words"
 )
 
 const (
 	NonMathQuesionType     = "non-math question"
 	UnsupportedOperandType = "unknown operand"
 	InvalidSyntaxType      = "invalid syntax"
+	DivisionByZeroType     = "division by zero"
+	OverflowType           = "overflow"
+	DomainType             = "math domain error"
+	UnsupportedLocaleType  = "unsupported locale"
+	UndefinedVariableType  = "undefined variable"
+	NoPreviousResultType   = "no previous result"
+	DimensionMismatchType  = "dimension mismatch"
+	InvalidDateType        = "invalid date"
+	UnknownTimeZoneType    = "unknown time zone"
 )
 
 type ErrorResponse struct {
-	Error string `json:"message"`
+	Error      string              `json:"message"`
+	Diagnostic *DiagnosticResponse `json:"diagnostic,omitempty"`
+}
+
+// DiagnosticResponse points at the part of the expression that caused an
+// error. Offset and Length are in bytes. Suggestions lists the known words
+// closest to a word that couldn't be read.
+type DiagnosticResponse struct {
+	Offset      int      `json:"offset"`
+	Length      int      `json:"length"`
+	Text        string   `json:"text"`
+	Expected    []string `json:"expected,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 type ExpressionErrorResponse struct {
@@ -25,14 +89,117 @@ type ExpressionErrorResponse struct {
 }
 
 type ValidateResponse struct {
-	Valid  bool   `json:"valid"`
-	Reason string `json:"reason,omitempty"`
+	Valid      bool                `json:"valid"`
+	Reason     string              `json:"reason,omitempty"`
+	Diagnostic *DiagnosticResponse `json:"diagnostic,omitempty"`
 }
 
+// EvaluateResponse holds the result of an evaluation. Type is NumberResult,
+// DateResult for a date, or BooleanResult for the answer to a yes/no
+// question. Answer is the result written as a sentence, e.g. "5 plus 3 is
+// 8.", when the request asks for one, and Trace lists the steps of the
+// evaluation when the request asks for a trace.
 type EvaluateResponse struct {
-	Result int `json:"result"`
+	Result     Number         `json:"result"`
+	Type       string         `json:"type"`
+	Arithmetic string         `json:"arithmetic"`
+	Answer     string         `json:"answer,omitempty"`
+	Trace      []StepResponse `json:"trace,omitempty"`
+}
+
+// StepResponse is one operation applied while evaluating an expression, in
+// the order they are applied. Text shows the working, e.g. "2 plus 3 = 5",
+// and Offset and Length locate the operator in the expression, in bytes.
+type StepResponse struct {
+	Text     string   `json:"text"`
+	Operator string   `json:"operator"`
+	Operands []Number `json:"operands"`
+	Result   Number   `json:"result"`
+	Offset   int      `json:"offset"`
+	Length   int      `json:"length"`
+}
+
+// SentencesResponse holds the outcome of each sentence of an input holding
+// several questions, e.g. "What is 1 plus 1? What is 2 plus 2?", in the
+// order they appear.
+type SentencesResponse struct {
+	Sentences []SentenceResponse `json:"sentences"`
+}
+
+// SentenceResponse holds either the result of a sentence, as in an
+// EvaluateResponse, or the error it failed with, as in an ErrorResponse.
+type SentenceResponse struct {
+	Sentence   string              `json:"sentence"`
+	Result     Number              `json:"result,omitempty"`
+	Type       string              `json:"type,omitempty"`
+	Arithmetic string              `json:"arithmetic,omitempty"`
+	Answer     string              `json:"answer,omitempty"`
+	Trace      []StepResponse      `json:"trace,omitempty"`
+	Error      string              `json:"message,omitempty"`
+	Diagnostic *DiagnosticResponse `json:"diagnostic,omitempty"`
 }
 
+// VariablesResponse lists the variables assigned in a session.
+type VariablesResponse struct {
+	Variables map[string]Number `json:"variables"`
+}
+
+// ExpressionRequest is the body of a request to evaluate or validate an
+// expression. Order is PrecedenceOrder, the default, or LeftToRightOrder for
+// clients that rely on the original semantics. Locale selects the language
+// of the expression, e.g. "de", and takes precedence over the
+// Accept-Language header. TimeZone is the IANA name of the time zone dates
+// are evaluated in, e.g. "Europe/Sofia", and defaults to UTC. Format is one
+// of NumberFormat, SentenceFormat and WordsFormat, and can also be given in
+// the "format" query parameter. Trace asks for the steps of the evaluation,
+// as does the "trace=true" query parameter.
 type ExpressionRequest struct {
 	Expression string `json:"expression"`
+	Arithmetic string `json:"arithmetic,omitempty"`
+	Order      string `json:"order,omitempty"`
+	Locale     string `json:"locale,omitempty"`
+	TimeZone   string `json:"timezone,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Trace      bool   `json:"trace,omitempty"`
+}
+
+// Number holds the textual form of a result. It is encoded as a JSON number
+// whenever it is a valid number literal, as a JSON boolean when it is the
+// answer to a yes/no question and as a JSON string otherwise, e.g. the
+// rational "7/2".
+type Number string
+
+func (n Number) MarshalJSON() ([]byte, error) {
+	if n == "true" || n == "false" {
+		return []byte(n), nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal([]byte(n), &number); err == nil {
+		return []byte(n), nil
+	}
+
+	return json.Marshal(string(n))
+}
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*n = Number(str)
+		return nil
+	}
+
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		*n = Number(strconv.FormatBool(boolean))
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+
+	*n = Number(number)
+	return nil
 }
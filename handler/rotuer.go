@@ -6,12 +6,15 @@ const (
 	EvaluateEndpoint            = "/evaluate"
 	ValidateEndpoint            = "/validate"
 	GetExpressionErrorsEndpoint = "/errors"
+	VariablesEndpoint           = "/variables"
 )
 
 type expressionHandler interface {
 	Evaluate(w http.ResponseWriter, r *http.Request)
 	Validate(w http.ResponseWriter, r *http.Request)
 	GetExpressionErrors(w http.ResponseWriter, r *http.Request)
+	GetVariables(w http.ResponseWriter, r *http.Request)
+	ClearVariables(w http.ResponseWriter, r *http.Request)
 }
 
 type Router struct {
@@ -23,6 +26,13 @@ func NewRouter(handler expressionHandler) *Router {
 	mux.HandleFunc(EvaluateEndpoint, handler.Evaluate)
 	mux.HandleFunc(ValidateEndpoint, handler.Validate)
 	mux.HandleFunc(GetExpressionErrorsEndpoint, handler.GetExpressionErrors)
+	mux.HandleFunc(VariablesEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			handler.ClearVariables(w, r)
+			return
+		}
+		handler.GetVariables(w, r)
+	})
 
 	return &Router{
 		Handler: mux,
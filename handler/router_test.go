@@ -13,6 +13,8 @@ type StubExpressionHandler struct {
 	spyEvaluate            bool
 	spyValidate            bool
 	spyGetExpressionErrors bool
+	spyGetVariables        bool
+	spyClearVariables      bool
 }
 
 func (s *StubExpressionHandler) Evaluate(w http.ResponseWriter, r *http.Request) {
@@ -27,6 +29,14 @@ func (s *StubExpressionHandler) GetExpressionErrors(w http.ResponseWriter, r *ht
 	s.spyGetExpressionErrors = true
 }
 
+func (s *StubExpressionHandler) GetVariables(w http.ResponseWriter, r *http.Request) {
+	s.spyGetVariables = true
+}
+
+func (s *StubExpressionHandler) ClearVariables(w http.ResponseWriter, r *http.Request) {
+	s.spyClearVariables = true
+}
+
 func TestRouting(t *testing.T) {
 	t.Run("routes evaluation requests for Evaluate handler", func(t *testing.T) {
 		request, _ := http.NewRequest(http.MethodGet, handler.EvaluateEndpoint, nil)
@@ -81,4 +91,30 @@ func TestRouting(t *testing.T) {
 		assert.Equal(t, txHandler.spyValidate, false)
 		assert.Equal(t, txHandler.spyGetExpressionErrors, true)
 	})
+
+	t.Run("routes variables requests for GetVariables handler", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, handler.VariablesEndpoint, nil)
+		response := httptest.NewRecorder()
+
+		txHandler := &StubExpressionHandler{}
+		router := handler.NewRouter(txHandler)
+
+		router.Handler.ServeHTTP(response, request)
+
+		assert.Equal(t, txHandler.spyGetVariables, true)
+		assert.Equal(t, txHandler.spyClearVariables, false)
+	})
+
+	t.Run("routes variables delete requests for ClearVariables handler", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodDelete, handler.VariablesEndpoint, nil)
+		response := httptest.NewRecorder()
+
+		txHandler := &StubExpressionHandler{}
+		router := handler.NewRouter(txHandler)
+
+		router.Handler.ServeHTTP(response, request)
+
+		assert.Equal(t, txHandler.spyGetVariables, false)
+		assert.Equal(t, txHandler.spyClearVariables, true)
+	})
 }
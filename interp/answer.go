@@ -0,0 +1,285 @@
+package interp
+
+import (
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AnswerFormat selects how Answer writes the numbers of an answer.
+type AnswerFormat int
+
+const (
+	// AnswerSentence writes numbers in digits, e.g. "5 plus 3 is 8.".
+	AnswerSentence AnswerFormat = iota
+	// AnswerWords spells numbers and units out, e.g. "Five plus three is
+	// eight.".
+	AnswerWords
+)
+
+// Answer writes the answer to a statement as an English sentence by
+// restating the statement from the tokens lexed from input, so
+// "What is 5 plus 3?" is answered with "5 plus 3 is 8.", "Let x be 2." with
+// "x is 2." and "Is 5 greater than 3?" with "Yes, 5 is greater than 3.". The
+// statement is restated in the words it was written in, as they appear in
+// input, so it must have been read in English. The words format spells out
+// the numbers and the names of units, e.g. "five thousand three hundred
+// meters" rather than "5300 m".
+func Answer(input string, tokens []Token, result Value, format AnswerFormat) string {
+	if len(tokens) > 1 {
+		if _, ok := tokens[0].(*AssignmentToken); ok {
+			name := originalText(input, tokens[1])
+			return name + " is " + answerValue(result, format) + "."
+		}
+	}
+
+	words := []string{}
+	yesNo, restated := false, false
+	for _, token := range tokens {
+		switch token.(type) {
+		case *QuestionToken, *PunctuationToken:
+			continue
+		case *YesNoQuestionToken:
+			yesNo = true
+			continue
+		case *ComparisonToken:
+			// "is 5 greater than 3" is restated as "5 is greater than 3".
+			if yesNo && !restated {
+				words = append(words, "is")
+				restated = true
+			}
+		}
+
+		value := originalText(input, token)
+		if _, ok := token.(*NumberToken); ok && format == AnswerWords {
+			value = spellNumber(token.GetToken().(string))
+		}
+		words = append(words, value)
+	}
+
+	statement := joinWords(words)
+	if !yesNo {
+		return capitalise(statement+" is "+answerValue(result, format), tokens) + "."
+	}
+
+	if result.(Boolean) {
+		return "Yes, " + statement + "."
+	}
+	return "No, it is not true that " + statement + "."
+}
+
+// originalText returns the text the token was read from in input, with its
+// whitespace collapsed to single spaces.
+func originalText(input string, token Token) string {
+	pos := token.GetPosition()
+	return strings.Join(strings.Fields(input[pos.Offset:pos.Offset+pos.Length]), " ")
+}
+
+// joinWords joins the words of a statement with spaces, except before a
+// separator or a closing parenthesis and after an opening one.
+func joinWords(words []string) string {
+	var builder strings.Builder
+	for i, word := range words {
+		if i > 0 && !strings.HasPrefix(word, ",") && word != ")" && words[i-1] != "(" {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(word)
+	}
+	return builder.String()
+}
+
+// capitalise capitalises the first letter of a sentence unless it starts
+// with the name of a variable, whose case is part of the name.
+func capitalise(sentence string, tokens []Token) string {
+	for _, token := range tokens {
+		if _, ok := token.(*QuestionToken); ok {
+			continue
+		}
+		if _, ok := token.(*IdentifierToken); ok {
+			return sentence
+		}
+		break
+	}
+
+	first, size := utf8.DecodeRuneInString(sentence)
+	return string(unicode.ToUpper(first)) + sentence[size:]
+}
+
+func answerValue(result Value, format AnswerFormat) string {
+	if format != AnswerWords {
+		return result.String()
+	}
+
+	switch result := result.(type) {
+	case *date, *numeral:
+		return result.String()
+	case *quantity:
+		if result.unit != nil {
+			value := result.value.String()
+			return spellNumber(value) + " " + unitName(result.unit, value)
+		}
+	}
+
+	return spellNumber(result.String())
+}
+
+// unitName writes the English name of a unit for a quantity with the given
+// value, e.g. "meter" for "1" and "meters" for "5300". The plural is the
+// second of the English phrases of the unit.
+func unitName(unit *Unit, value string) string {
+	phrases := English.Units[unit.Name]
+	if value == "1" || value == "-1" || len(phrases) < 2 {
+		return unit.Name
+	}
+	return phrases[1]
+}
+
+var spelledNumberRegex = regexp.MustCompile(`^(-?)(\d+)(?:\.(\d+)|/(\d+))?$`)
+
+// spellNumber spells out a number written in digits, e.g. "twenty-three"
+// for "23", "minus one point five" for "-1.5" and "seven over two" for
+// "7/2". A number in exponent form, e.g. "1e+21", is spelled out from its
+// digits. Numbers it can't spell out are returned as they are.
+func spellNumber(number string) string {
+	match := spelledNumberRegex.FindStringSubmatch(number)
+	if match == nil {
+		if !strings.ContainsAny(number, "eE") {
+			return number
+		}
+		f, err := strconv.ParseFloat(number, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return number
+		}
+		return spellNumber(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+
+	spelled := spellInteger(match[2])
+	if spelled == "" {
+		return number
+	}
+
+	switch {
+	case match[3] != "":
+		digits := []string{}
+		for _, digit := range match[3] {
+			digits = append(digits, spellInteger(string(digit)))
+		}
+		spelled += " point " + strings.Join(digits, " ")
+	case match[4] != "":
+		denominator := spellInteger(match[4])
+		if denominator == "" {
+			return number
+		}
+		spelled += " over " + denominator
+	}
+
+	if match[1] != "" {
+		spelled = "minus " + spelled
+	}
+	return spelled
+}
+
+// spellInteger spells out a whole number written in digits. A thousand or
+// more of the largest scale of the English number words are counted in that
+// scale, e.g. "one thousand trillion" for 10^15.
+func spellInteger(digits string) string {
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok || n.Sign() < 0 {
+		return ""
+	}
+
+	words := English.NumberWords
+	if n.Sign() == 0 {
+		return words.Zero
+	}
+	return spellWhole(n, sortedScales(words.Scales), words)
+}
+
+// spellWhole spells out a positive whole number with the scales sorted from
+// the largest to the smallest. The last part is joined with the conjunction
+// when it is below a hundred, as it is after a hundred, e.g. "one thousand
+// and five" like "one hundred and five".
+func spellWhole(n *big.Int, scales []scaleWord, words NumberWords) string {
+	parts := []string{}
+
+	largest := big.NewInt(scales[0].value)
+	if n.Cmp(new(big.Int).Mul(largest, big.NewInt(1000))) >= 0 {
+		count, rest := new(big.Int).QuoRem(n, largest, new(big.Int))
+		parts = append(parts, spellWhole(count, scales, words)+" "+scales[0].word)
+		n = rest
+	}
+
+	value := n.Int64()
+	for _, scale := range scales {
+		if value >= scale.value {
+			parts = append(parts, spellHundreds(value/scale.value, words)+" "+scale.word)
+			value %= scale.value
+		}
+	}
+	if value > 0 {
+		if value < 100 && len(parts) > 0 {
+			parts = append(parts, words.Conjunction)
+		}
+		parts = append(parts, spellHundreds(value, words))
+	}
+	return strings.Join(parts, " ")
+}
+
+type scaleWord struct {
+	word  string
+	value int64
+}
+
+// sortedScales returns the scales from the largest to the smallest.
+func sortedScales(scales map[string]int64) []scaleWord {
+	sorted := []scaleWord{}
+	for word, value := range scales {
+		sorted = append(sorted, scaleWord{word: word, value: value})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].value > sorted[j].value
+	})
+	return sorted
+}
+
+// spellHundreds spells out a number from 1 to 999, e.g. "one hundred and
+// five" or "forty-two".
+func spellHundreds(value int64, words NumberWords) string {
+	parts := []string{}
+	if value >= 100 {
+		parts = append(parts, wordFor(value/100, words.Ones)+" "+words.Hundred)
+		value %= 100
+		if value > 0 {
+			parts = append(parts, words.Conjunction)
+		}
+	}
+
+	switch {
+	case value >= 20:
+		tens := wordFor(value-value%10, words.Tens)
+		if value%10 > 0 {
+			tens += "-" + wordFor(value%10, words.Ones)
+		}
+		parts = append(parts, tens)
+	case value >= 10:
+		parts = append(parts, wordFor(value, words.Teens))
+	case value > 0:
+		parts = append(parts, wordFor(value, words.Ones))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func wordFor(value int64, words map[string]int64) string {
+	for word, v := range words {
+		if v == value {
+			return word
+		}
+	}
+	return ""
+}
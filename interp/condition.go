@@ -0,0 +1,59 @@
+package interp
+
+// Comparison is a relation between two numbers that a yes/no question asks
+// about, e.g. the "greater than" in "Is 5 greater than 3?".
+type Comparison int
+
+const (
+	GreaterThan Comparison = iota
+	LessThan
+	EqualTo
+	AtLeast
+	AtMost
+)
+
+// holds reports whether the comparison holds for two numbers whose Cmp
+// returned cmp.
+func (c Comparison) holds(cmp int) bool {
+	switch c {
+	case GreaterThan:
+		return cmp > 0
+	case LessThan:
+		return cmp < 0
+	case EqualTo:
+		return cmp == 0
+	case AtLeast:
+		return cmp >= 0
+	case AtMost:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// Conjunction joins the comparisons of a yes/no question. "and" binds
+// tighter than "or", so "Is 1 less than 2 or 2 less than 1 and 3 less than
+// 4?" asks whether the first comparison or both of the others hold.
+type Conjunction int
+
+const (
+	And Conjunction = iota
+	Or
+)
+
+func (c Conjunction) precedence() int {
+	if c == And {
+		return 2
+	}
+	return 1
+}
+
+// Boolean is the answer to a yes/no question.
+type Boolean bool
+
+func (b Boolean) String() string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
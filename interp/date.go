@@ -0,0 +1,188 @@
+package interp
+
+import (
+	"math/big"
+	"time"
+)
+
+// dateLayouts are the layouts of the date literals matched by
+// DateTokenPattern. Input is read in lower case, hence the "t".
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02t15:04",
+	"2006-01-02t15:04:05",
+}
+
+// maxShiftDays bounds the number of days a date can be moved by, which keeps
+// the day count well within an int while still reaching any year a date can
+// be written in.
+const maxShiftDays = 10000 * 366
+
+var secondsPerDay = big.NewRat(86400, 1)
+
+// date is a point in time. A duration, i.e. a quantity of time, can be added
+// to or subtracted from a date, and subtracting two dates gives the duration
+// between them. Every other operation involving a date is reported as
+// ErrDimensionMismatch.
+type date struct {
+	time       time.Time
+	arithmetic Arithmetic
+}
+
+func isDate(number Number) bool {
+	_, ok := number.(*date)
+	return ok
+}
+
+// parseDate reads a date literal, e.g. "2024-03-01" or "2024-03-01t14:30",
+// in the given location. A date that doesn't exist, such as "2024-02-30", is
+// reported as ErrInvalidDate.
+func parseDate(literal string, location *time.Location, arithmetic Arithmetic) (Number, error) {
+	for _, layout := range dateLayouts {
+		if len(layout) != len(literal) {
+			continue
+		}
+
+		t, err := time.ParseInLocation(layout, literal, location)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		return &date{time: t, arithmetic: arithmetic}, nil
+	}
+	return nil, ErrInvalidDate
+}
+
+// parseStoredDate reads the textual form of a date produced by String. The
+// second result is false when the value isn't a date.
+func parseStoredDate(value string, location *time.Location, arithmetic Arithmetic) (Number, bool) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, location); err == nil {
+		return &date{time: t, arithmetic: arithmetic}, true
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return &date{time: t.In(location), arithmetic: arithmetic}, true
+	}
+	return nil, false
+}
+
+func (d *date) Add(other Number) (Number, error) {
+	q, ok := other.(*quantity)
+	if !ok || q.unit == nil || q.unit.Dimension != Time {
+		return nil, ErrDimensionMismatch
+	}
+	return d.shift(exactValue(q.value), q.unit)
+}
+
+func (d *date) Sub(other Number) (Number, error) {
+	if o, ok := other.(*date); ok {
+		return d.since(o)
+	}
+
+	q, ok := other.(*quantity)
+	if !ok || q.unit == nil || q.unit.Dimension != Time {
+		return nil, ErrDimensionMismatch
+	}
+	return d.shift(new(big.Rat).Neg(exactValue(q.value)), q.unit)
+}
+
+// shift moves a date by an amount of a unit of time. Whole days and weeks
+// are calendar days, so that a day added across a daylight saving change
+// keeps the time of day, while the other units are elapsed time.
+func (d *date) shift(amount *big.Rat, unit *Unit) (Number, error) {
+	seconds := new(big.Rat).Mul(amount, unit.Factor)
+
+	days := new(big.Rat).Quo(seconds, secondsPerDay)
+	if unit.Factor.Cmp(secondsPerDay) >= 0 && days.IsInt() {
+		if days.Num().CmpAbs(big.NewInt(maxShiftDays)) > 0 {
+			return nil, ErrOverflow
+		}
+		return d.at(d.time.AddDate(0, 0, int(days.Num().Int64())))
+	}
+
+	nanoseconds := seconds.Mul(seconds, big.NewRat(int64(time.Second), 1))
+	if !nanoseconds.IsInt() {
+		return nil, ErrDomain
+	}
+	if !nanoseconds.Num().IsInt64() {
+		return nil, ErrOverflow
+	}
+	return d.at(d.time.Add(time.Duration(nanoseconds.Num().Int64())))
+}
+
+// at returns the date at t, reporting dates outside the years that can be
+// written as a date literal as ErrOverflow.
+func (d *date) at(t time.Time) (Number, error) {
+	if t.Year() < 1 || t.Year() > 9999 {
+		return nil, ErrOverflow
+	}
+	return &date{time: t, arithmetic: d.arithmetic}, nil
+}
+
+// since returns the time elapsed from another date in the largest unit that
+// measures it exactly, e.g. "45 d" or "90 min".
+func (d *date) since(other *date) (Number, error) {
+	seconds := new(big.Rat).SetInt64(d.time.Unix() - other.time.Unix())
+	seconds.Add(seconds, big.NewRat(int64(d.time.Nanosecond()-other.time.Nanosecond()), int64(time.Second)))
+
+	unit := elapsedUnit(seconds)
+	value, err := exactNumber(seconds.Quo(seconds, unit.Factor), d.arithmetic)
+	if err != nil {
+		return nil, err
+	}
+	return newQuantity(value, unit, d.arithmetic), nil
+}
+
+func elapsedUnit(seconds *big.Rat) *Unit {
+	for _, name := range []string{"day", "hour", "minute"} {
+		unit, _ := lookupUnit(name)
+		if new(big.Rat).Quo(seconds, unit.Factor).IsInt() {
+			return unit
+		}
+	}
+
+	unit, _ := lookupUnit("second")
+	return unit
+}
+
+func (d *date) Mul(other Number) (Number, error) {
+	return nil, ErrDimensionMismatch
+}
+
+func (d *date) Div(other Number) (Number, error) {
+	return nil, ErrDimensionMismatch
+}
+
+func (d *date) Pow(other Number) (Number, error) {
+	return nil, ErrDimensionMismatch
+}
+
+func (d *date) Mod(other Number) (Number, error) {
+	return nil, ErrDimensionMismatch
+}
+
+func (d *date) Neg() (Number, error) {
+	return nil, ErrDimensionMismatch
+}
+
+func (d *date) Sqrt() (Number, error) {
+	return nil, ErrDimensionMismatch
+}
+
+// Cmp orders dates in time. It is only meaningful between two dates, which
+// the caller checks with sameDimension.
+func (d *date) Cmp(other Number) int {
+	o, ok := other.(*date)
+	if !ok {
+		return 0
+	}
+	return d.time.Compare(o.time)
+}
+
+// String renders a date at midnight as "2024-03-01" and any other date in
+// RFC 3339, e.g. "2024-03-01T14:30:00+01:00".
+func (d *date) String() string {
+	hour, minute, second := d.time.Clock()
+	if hour == 0 && minute == 0 && second == 0 && d.time.Nanosecond() == 0 {
+		return d.time.Format(time.DateOnly)
+	}
+	return d.time.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,66 @@
+package interp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diagnostic is an error that points at the part of the input that caused
+// it. It wraps one of the package's error values, so it still matches them
+// through errors.Is. Suggestions lists the known words closest to a word
+// the lexer can't read.
+type Diagnostic struct {
+	err error
+
+	Position    Position
+	Text        string
+	Expected    []string
+	Suggestions []string
+}
+
+func NewDiagnostic(err error, pos Position, text string, expected []string) *Diagnostic {
+	return &Diagnostic{
+		err:      err,
+		Position: pos,
+		Text:     text,
+		Expected: expected,
+	}
+}
+
+func (d *Diagnostic) Error() string {
+	location := "end of input"
+	if d.Text != "" {
+		location = fmt.Sprintf("%q", d.Text)
+	}
+
+	msg := fmt.Sprintf("%s at %s (offset %d)", d.err.Error(), location, d.Position.Offset)
+	if len(d.Expected) > 0 {
+		msg += ", expected " + joinAlternatives(d.Expected)
+	}
+	if len(d.Suggestions) > 0 {
+		msg += ", " + didYouMean(d.Suggestions)
+	}
+	return msg
+}
+
+// didYouMean writes suggestions as a question, e.g. "did you mean 'plus'?".
+func didYouMean(suggestions []string) string {
+	quoted := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		quoted[i] = "'" + suggestion + "'"
+	}
+	return "did you mean " + joinAlternatives(quoted) + "?"
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.err
+}
+
+func joinAlternatives(alternatives []string) string {
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+
+	last := len(alternatives) - 1
+	return strings.Join(alternatives[:last], ", ") + " or " + alternatives[last]
+}
@@ -1,47 +1,337 @@
 package interp
 
-import "strconv"
+import (
+	"errors"
+	"time"
+)
 
-func Interpret(tokens []Token) int {
-	result := parseNumberToken(tokens[0].(*NumberToken))
+// Options configures how an expression tree is evaluated. Variables are read
+// from the Environment, and assignments are recorded in it when it is set.
+// Previous holds the textual form of the result of the previous evaluation,
+// which "the result" refers to, and is empty when there is none. Dates are
+// read and written in the Location, or in UTC when it is nil. The operations
+// applied are recorded in the Trace when it is set.
+type Options struct {
+	Arithmetic  Arithmetic
+	Environment Environment
+	Previous    string
+	Location    *time.Location
+	Trace       *Trace
+}
 
-	opPtr := 1
-	numPtr := 2
+func (o Options) location() *time.Location {
+	if o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
 
-	for numPtr < len(tokens) {
-		numToken := tokens[numPtr].(*NumberToken)
-		number := parseNumberToken(numToken)
+// Environment binds variable names to the textual form of their values, so
+// that a value bound in one arithmetic can be read in another.
+type Environment map[string]string
 
-		opToken := tokens[opPtr].(*OperandToken)
-		result = executeOperandToken(opToken, result, number)
+// Interpret evaluates an expression tree produced by Parse or ParseLeftToRight.
+func Interpret(node Node, opts Options) (Value, error) {
+	return defaultOperators.Interpret(node, opts)
+}
 
-		opPtr += 2
-		numPtr += 2
+// Interpret evaluates an expression tree using the implementations of the
+// operators held by the registry. Yes/no questions evaluate to a Boolean and
+// every other statement to a Number.
+func (o *OperatorRegistry) Interpret(node Node, opts Options) (Value, error) {
+	switch node.(type) {
+	case *ComparisonNode, *ConjunctionNode:
+		return o.interpretCondition(node, opts)
+	default:
+		result, err := o.interpretNumber(node, opts)
+		if err != nil {
+			return nil, err
+		}
+		return settle(result, opts.Arithmetic)
 	}
+}
+
+// interpretCondition evaluates the comparisons of a yes/no question. The
+// right side of a conjunction is only evaluated when the left side doesn't
+// already decide the answer.
+func (o *OperatorRegistry) interpretCondition(node Node, opts Options) (Boolean, error) {
+	switch n := node.(type) {
+	case *ComparisonNode:
+		left, err := o.interpretNumber(n.Left, opts)
+		if err != nil {
+			return false, err
+		}
+
+		right, err := o.interpretNumber(n.Right, opts)
+		if err != nil {
+			return false, err
+		}
 
-	return result
+		comparison, ok := o.locale.comparison(n.Comparison.GetToken().(string))
+		if !ok {
+			return false, tokenError(n.Comparison, ErrUnsupportedOperation)
+		}
+		if !sameDimension(left, right) {
+			return false, tokenError(n.Comparison, ErrDimensionMismatch)
+		}
+		if hasExactOperand([]Number{left, right}, opts.Arithmetic) {
+			operands := inDecimal([]Number{left, right})
+			left, right = operands[0], operands[1]
+		}
+
+		result := Boolean(comparison.holds(left.Cmp(right)))
+		opts.Trace.record(n.Comparison, Infix, []Value{left, right}, result)
+		return result, nil
+	case *ConjunctionNode:
+		conjunction, ok := o.locale.conjunction(n.Conjunction.GetToken().(string))
+		if !ok {
+			return false, tokenError(n.Conjunction, ErrUnsupportedOperation)
+		}
+
+		left, err := o.interpretCondition(n.Left, opts)
+		if err != nil {
+			return false, err
+		}
+		if (conjunction == And && !left) || (conjunction == Or && left) {
+			return left, nil
+		}
+
+		return o.interpretCondition(n.Right, opts)
+	default:
+		return false, NewInterpreterError("unknown node")
+	}
 }
 
-func parseNumberToken(token *NumberToken) int {
+func (o *OperatorRegistry) interpretNumber(node Node, opts Options) (Number, error) {
+	switch n := node.(type) {
+	case *NumberNode:
+		result, err := parseNumberToken(n.Number, opts.Arithmetic)
+		return result, tokenError(n.Number, err)
+	case *DateNode:
+		result, err := parseDate(n.Date.GetToken().(string), opts.location(), opts.Arithmetic)
+		return result, tokenError(n.Date, err)
+	case *BinaryNode:
+		left, err := o.interpretNumber(n.Left, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		operator, ok := o.lookup(n.Operand.GetToken().(string), Infix)
+		if !ok {
+			return nil, tokenError(n.Operand, ErrUnsupportedOperation)
+		}
+
+		right, err := o.interpretRightOperand(operator, left, n.Right, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := applyOperator(operator, []Number{left, right}, opts.Arithmetic)
+		if err != nil {
+			return nil, tokenError(n.Operand, err)
+		}
+
+		opts.Trace.record(n.Operand, Infix, []Value{left, right}, result)
+		return result, nil
+	case *UnaryNode:
+		child, err := o.interpretNumber(n.Child, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		operator, ok := o.lookupUnary(n.Operand.GetToken().(string))
+		if !ok {
+			return nil, tokenError(n.Operand, ErrUnsupportedOperation)
+		}
+
+		result, err := applyOperator(operator, []Number{child}, opts.Arithmetic)
+		if err != nil {
+			return nil, tokenError(n.Operand, err)
+		}
+
+		opts.Trace.record(n.Operand, operator.Notation, []Value{child}, result)
+		return result, nil
+	case *QuantityNode:
+		child, err := o.interpretNumber(n.Child, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		unit, ok := o.locale.unit(n.Unit.GetToken().(string))
+		if !ok {
+			return nil, tokenError(n.Unit, ErrUnsupportedOperation)
+		}
+		if unitOf(child) != nil || isDate(child) {
+			return nil, tokenError(n.Unit, ErrDimensionMismatch)
+		}
+
+		return newQuantity(child, unit, opts.Arithmetic), nil
+	case *ConversionNode:
+		value, err := o.interpretNumber(n.Expression, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		unit, ok := o.locale.unit(n.Unit.GetToken().(string))
+		if !ok {
+			return nil, tokenError(n.Unit, ErrUnsupportedOperation)
+		}
+
+		result, err := convertTo(value, unit)
+		if err != nil {
+			return nil, tokenError(n.Unit, err)
+		}
+
+		opts.Trace.recordConversion(n.Conversion, n.Unit, value, result)
+		return result, nil
+	case *NumeralSystemNode:
+		value, err := o.interpretNumber(n.Expression, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		system, ok := o.locale.numeralSystem(n.NumeralSystem.GetToken().(string))
+		if !ok {
+			return nil, tokenError(n.NumeralSystem, ErrUnsupportedOperation)
+		}
+
+		result, err := inNumeralSystem(value, system)
+		if err != nil {
+			return nil, tokenError(n.NumeralSystem, err)
+		}
+
+		opts.Trace.recordConversion(n.Conversion, n.NumeralSystem, value, result)
+		return result, nil
+	case *FunctionNode:
+		arguments := make([]Number, 0, len(n.Arguments))
+		for _, argument := range n.Arguments {
+			value, err := o.interpretNumber(argument, opts)
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, value)
+		}
+
+		operator, ok := o.lookup(n.Function.GetToken().(string), Function)
+		if !ok {
+			return nil, tokenError(n.Function, ErrUnsupportedOperation)
+		}
+
+		result, err := applyOperator(operator, arguments, opts.Arithmetic)
+		if err != nil {
+			return nil, tokenError(n.Function, err)
+		}
+
+		values := make([]Value, len(arguments))
+		for i, argument := range arguments {
+			values[i] = argument
+		}
+		opts.Trace.record(n.Function, Function, values, result)
+		return result, nil
+	case *VariableNode:
+		result, err := lookupVariable(n.Identifier, opts)
+		return result, tokenError(n.Identifier, err)
+	case *PreviousResultNode:
+		result, err := lookupPreviousResult(opts)
+		return result, tokenError(n.Reference, err)
+	case *AssignmentNode:
+		value, err := o.interpretNumber(n.Value, opts)
+		if err != nil {
+			return nil, err
+		}
+		value, err = settle(value, opts.Arithmetic)
+		if err != nil {
+			return nil, tokenError(n.Identifier, err)
+		}
+
+		if opts.Environment != nil {
+			opts.Environment[n.Identifier.GetToken().(string)] = value.String()
+		}
+		return value, nil
+	default:
+		return nil, NewInterpreterError("unknown node")
+	}
+}
+
+// interpretRightOperand evaluates the right operand of an infix operator.
+// A percentage on the right of a relative operator is taken of the left
+// operand, so "200 increased by 10 percent" adds 20.
+func (o *OperatorRegistry) interpretRightOperand(operator Operator, left Number, right Node, opts Options) (Number, error) {
+	if unary, ok := right.(*UnaryNode); ok && operator.Relative {
+		postfix, ok := o.lookup(unary.Operand.GetToken().(string), Postfix)
+		if ok && postfix.Percentage {
+			rate, err := o.interpretNumber(unary.Child, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			result, err := percentage(rate, left, opts.Arithmetic)
+			if err != nil {
+				return nil, tokenError(unary.Operand, err)
+			}
+
+			opts.Trace.record(unary.Operand, Postfix, []Value{rate}, result)
+			return result, nil
+		}
+	}
+
+	return o.interpretNumber(right, opts)
+}
+
+// tokenError points an error raised while evaluating a node at the token
+// the node was built from.
+func tokenError(token Token, err error) error {
+	if err == nil {
+		return nil
+	}
+	return NewDiagnostic(err, token.GetPosition(), token.GetToken().(string), nil)
+}
+
+func parseNumberToken(token *NumberToken, arithmetic Arithmetic) (Number, error) {
 	str := token.GetToken().(string)
-	num, _ := strconv.Atoi(str)
 
-	return num
+	return ParseNumber(str, arithmetic)
 }
 
-func executeOperandToken(token *OperandToken, left, right int) int {
-	var result int
+func lookupVariable(token *IdentifierToken, opts Options) (Number, error) {
+	value, ok := opts.Environment[token.GetToken().(string)]
+	if !ok {
+		return nil, ErrUndefinedVariable
+	}
 
-	switch token.GetToken().(string) {
-	case "plus":
-		result = left + right
-	case "minus":
-		result = left - right
-	case "multiplied by":
-		result = left * right
-	case "divided by":
-		result = left / right
+	return parseStoredNumber(value, opts)
+}
+
+func lookupPreviousResult(opts Options) (Number, error) {
+	if opts.Previous == "" {
+		return nil, ErrNoPreviousResult
 	}
 
-	return result
+	return parseStoredNumber(opts.Previous, opts)
+}
+
+// parseStoredNumber reads a value kept from an earlier evaluation, which may
+// be a quantity such as "5300 m", a date or a number written in another
+// numeral system such as "0x1f", in the arithmetic being used. A value that
+// the arithmetic can't represent, such as "3.5" in integer arithmetic, is
+// reported as ErrDomain.
+func parseStoredNumber(value string, opts Options) (Number, error) {
+	if number, ok := parseStoredDate(value, opts.location(), opts.Arithmetic); ok {
+		return number, nil
+	}
+	if number, ok := parseNumeral(value); ok {
+		value = number.String()
+	}
+
+	number, ok, err := parseQuantity(value, opts.Arithmetic)
+	if !ok {
+		number, err = ParseNumber(value, opts.Arithmetic)
+	}
+	if errors.Is(err, ErrOverflow) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, ErrDomain
+	}
+	return number, nil
 }
@@ -2,64 +2,367 @@ package interp
 
 import (
 	"errors"
+	"maps"
+	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/VitoNaychev/eval-web-service/service"
 )
 
 type LexFunc func(string) ([]Token, error)
-type ParseFunc func([]Token) ([]Token, error)
-type InterpFunc func([]Token) int
+type ParseFunc func([]Token) (Node, error)
+type InterpFunc func(Node, Options) (Value, error)
 
+// stages are the functions an expression is evaluated with. parseLeftToRight
+// replaces parse for the evaluations that ask for the original left to right
+// order of operations. english tells the stages that read English, whose
+// statements are the only ones Answer can restate.
+type stages struct {
+	lex              LexFunc
+	parse            ParseFunc
+	parseLeftToRight ParseFunc
+	interp           InterpFunc
+	english          bool
+}
+
+// InterpMW adapts the interpreter to the service. Expressions are read with
+// the stages given to NewInterpMW unless the caller prefers one of the
+// locales added with AddLocale.
 type InterpMW struct {
-	lex    LexFunc
-	parse  ParseFunc
-	interp InterpFunc
+	stages
+	locales map[string]stages
 }
 
-func NewInterpMW(lex LexFunc, parse ParseFunc, interp InterpFunc) *InterpMW {
+func NewInterpMW(lex LexFunc, parse, parseLeftToRight ParseFunc, interp InterpFunc) *InterpMW {
 	return &InterpMW{
-		lex:    lex,
-		parse:  parse,
-		interp: interp,
+		stages: stages{
+			lex:              lex,
+			parse:            parse,
+			parseLeftToRight: parseLeftToRight,
+			interp:           interp,
+			english:          true,
+		},
+		locales: map[string]stages{},
 	}
 }
 
-func (i *InterpMW) Validate(input string) (bool, error) {
-	tokens, err := i.lex(input)
-	if err != nil {
-		return false, interpErrorToServiceError(err)
+// AddLocale registers the stages that read expressions in the locale with
+// the given language tag, e.g. "de".
+func (i *InterpMW) AddLocale(tag string, lex LexFunc, parse, parseLeftToRight ParseFunc, interp InterpFunc) {
+	tag = strings.ToLower(tag)
+	language, _, _ := strings.Cut(tag, "-")
+	i.locales[tag] = stages{
+		lex:              lex,
+		parse:            parse,
+		parseLeftToRight: parseLeftToRight,
+		interp:           interp,
+		english:          language == English.Tag,
+	}
+}
+
+// localeStages returns the stages of the first supported locale among the
+// preferred ones. A "*" accepts the default stages, as does an empty list.
+func (i *InterpMW) localeStages(tags []string) (stages, error) {
+	if len(tags) == 0 {
+		return i.stages, nil
+	}
+
+	for _, tag := range tags {
+		if tag == "*" {
+			return i.stages, nil
+		}
+
+		tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+		if s, ok := i.locales[tag]; ok {
+			return s, nil
+		}
+
+		language, _, _ := strings.Cut(tag, "-")
+		if s, ok := i.locales[language]; ok {
+			return s, nil
+		}
 	}
 
-	_, err = i.parse(tokens)
+	return stages{}, service.ErrUnsupportedLocale
+}
+
+// Validate reports whether every sentence of the input is valid, e.g. both
+// questions of "What is 1 plus 1? What is 2 plus 2?". The error of the
+// first sentence that isn't is returned with a diagnostic located in the
+// whole input.
+func (i *InterpMW) Validate(input string, opts service.ValidateOptions) (bool, error) {
+	s, err := i.localeStages(opts.Locales)
 	if err != nil {
-		return false, interpErrorToServiceError(err)
+		return false, err
+	}
+
+	for _, sentence := range SplitInput(input) {
+		tokens, err := lexSentence(s, input, sentence)
+		if err != nil {
+			return false, interpErrorToServiceError(input, err)
+		}
+
+		_, err = s.parse(tokens)
+		if err != nil {
+			return false, interpErrorToServiceError(input, err)
+		}
 	}
 
 	return true, nil
 }
 
-func (i *InterpMW) Evaluate(input string) (int, error) {
-	tokens, err := i.lex(input)
+// EvaluateSentences evaluates each sentence of the input in order. The
+// sentences share their variables and each one reads the result of the last
+// sentence before it that produced a number or a date as the previous
+// result. An error in a sentence, including a word that can't be lexed, is
+// reported in its SentenceResult with a diagnostic located in the whole
+// input, while errors that concern the whole input, such as an unsupported
+// locale, are returned.
+func (i *InterpMW) EvaluateSentences(input string, opts service.EvaluateOptions) ([]service.SentenceResult, error) {
+	s, interpOpts, err := i.evaluation(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []service.SentenceResult{}
+	for _, sentence := range SplitInput(input) {
+		text := input[sentence.Offset : sentence.Offset+sentence.Length]
+
+		tokens, err := lexSentence(s, input, sentence)
+		if err != nil {
+			results = append(results, service.SentenceResult{
+				Sentence: text,
+				Err:      interpErrorToServiceError(input, err),
+			})
+			continue
+		}
+
+		interpOpts.Trace = newTrace(opts)
+		result, err := evaluateTokens(s, tokens, interpOpts)
+		if err != nil {
+			results = append(results, service.SentenceResult{
+				Sentence: text,
+				Err:      interpErrorToServiceError(input, err),
+			})
+			continue
+		}
+
+		if _, ok := result.(Boolean); !ok {
+			interpOpts.Previous = result.String()
+		}
+		sentenceOpts := interpOpts
+		sentenceOpts.Environment = maps.Clone(interpOpts.Environment)
+		results = append(results, service.SentenceResult{
+			Sentence: text,
+			Result:   toServiceResult(input, tokens, result, opts, sentenceOpts),
+		})
+	}
+
+	return results, nil
+}
+
+// lexSentence lexes the sentence of the input at pos. The positions of its
+// tokens, and of the diagnostics of its errors, are moved by the start of the
+// sentence, so that they are those in the whole input.
+func lexSentence(s stages, input string, pos Position) ([]Token, error) {
+	tokens, err := s.lex(input[pos.Offset : pos.Offset+pos.Length])
+	if err != nil {
+		var diagnostic *Diagnostic
+		if errors.As(err, &diagnostic) {
+			diagnostic.Position.Offset += pos.Offset
+		}
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		tokenPos := token.GetPosition()
+		tokenPos.Offset += pos.Offset
+		token.SetPosition(tokenPos)
+	}
+	return tokens, nil
+}
+
+// evaluation returns the stages and the interpreter options an evaluation
+// with the given options runs with.
+func (i *InterpMW) evaluation(opts service.EvaluateOptions) (stages, Options, error) {
+	s, err := i.localeStages(opts.Locales)
+	if err != nil {
+		return stages{}, Options{}, err
+	}
+	if opts.Order == service.OrderLeftToRight {
+		s.parse = s.parseLeftToRight
+	}
+	// Answers are written in English and restate the words of the
+	// statement, so a statement read in another language can't be answered.
+	if opts.Format != service.FormatNumber && !s.english {
+		return stages{}, Options{}, service.ErrUnsupportedLocale
+	}
+
+	location, err := loadLocation(opts.TimeZone)
+	if err != nil {
+		return stages{}, Options{}, err
+	}
+
+	environment := Environment{}
+	for name, value := range opts.Variables {
+		environment[name] = value
+	}
+
+	return s, Options{
+		Arithmetic:  serviceArithmeticToArithmetic(opts.Arithmetic),
+		Environment: environment,
+		Previous:    opts.Previous,
+		Location:    location,
+	}, nil
+}
+
+func evaluateTokens(s stages, tokens []Token, opts Options) (Value, error) {
+	tree, err := s.parse(tokens)
 	if err != nil {
-		return -1, interpErrorToServiceError(err)
+		return nil, err
+	}
+
+	return s.interp(tree, opts)
+}
+
+// newTrace returns the Trace an evaluation records its steps in, or nil when
+// opts don't ask for them.
+func newTrace(opts service.EvaluateOptions) *Trace {
+	if !opts.Trace {
+		return nil
+	}
+	return &Trace{}
+}
+
+// toServiceResult converts the value of a statement to a Result, answering
+// with a sentence built from the tokens of the statement when opts ask for
+// one. The variables and the steps of the Result are those of interpOpts.
+func toServiceResult(input string, tokens []Token, v Value, opts service.EvaluateOptions, interpOpts Options) service.Result {
+	result := service.Result{
+		Value:      v.String(),
+		Type:       valueToResultType(v),
+		Arithmetic: opts.Arithmetic,
+		Variables:  interpOpts.Environment,
+	}
+
+	if interpOpts.Trace != nil {
+		result.Steps = make([]service.Step, len(interpOpts.Trace.Steps))
+		for i, step := range interpOpts.Trace.Steps {
+			result.Steps[i] = service.Step{
+				Text:     step.String(),
+				Operator: step.Operator,
+				Operands: step.Operands,
+				Result:   step.Result,
+				Offset:   step.Position.Offset,
+				Length:   step.Position.Length,
+			}
+		}
+	}
+
+	switch opts.Format {
+	case service.FormatSentence:
+		result.Answer = Answer(input, tokens, v, AnswerSentence)
+	case service.FormatWords:
+		result.Answer = Answer(input, tokens, v, AnswerWords)
 	}
 
-	significantTokens, err := i.parse(tokens)
+	return result
+}
+
+// loadLocation returns the time zone with the given IANA name, or UTC for an
+// empty name. The time zone database is embedded in the binary and "Local"
+// is refused, so that an expression evaluates the same on every host.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, service.ErrUnknownTimeZone
+	}
+
+	location, err := time.LoadLocation(name)
 	if err != nil {
-		return -1, interpErrorToServiceError(err)
+		return nil, service.ErrUnknownTimeZone
+	}
+	return location, nil
+}
+
+func valueToResultType(v Value) service.ResultType {
+	switch v.(type) {
+	case Boolean:
+		return service.ResultBoolean
+	case *date:
+		return service.ResultDate
+	default:
+		return service.ResultNumber
+	}
+}
+
+func serviceArithmeticToArithmetic(a service.Arithmetic) Arithmetic {
+	switch a {
+	case service.ArithmeticFloat:
+		return ArithmeticFloat
+	case service.ArithmeticDecimal:
+		return ArithmeticDecimal
+	case service.ArithmeticRational:
+		return ArithmeticRational
+	case service.ArithmeticBigInteger:
+		return ArithmeticBigInteger
+	default:
+		return ArithmeticInteger
 	}
+}
+
+// interpErrorToServiceError maps err to the matching service error and, when
+// err is a Diagnostic, attaches its location in the input.
+func interpErrorToServiceError(input string, err error) error {
+	serviceErr := interpErrorToServiceErrorType(err)
 
-	return i.interp(significantTokens), nil
+	var diagnostic *Diagnostic
+	if !errors.As(err, &diagnostic) {
+		return serviceErr
+	}
+
+	pos := diagnostic.Position
+	serviceDiagnostic := service.Diagnostic{
+		Offset:      pos.Offset,
+		Length:      pos.Length,
+		Text:        input[pos.Offset : pos.Offset+pos.Length],
+		Expected:    diagnostic.Expected,
+		Suggestions: diagnostic.Suggestions,
+	}
+	if len(diagnostic.Suggestions) > 0 {
+		serviceDiagnostic.Hint = didYouMean(diagnostic.Suggestions)
+	}
+	return service.NewDiagnosticError(serviceErr, serviceDiagnostic)
 }
 
-func interpErrorToServiceError(err error) error {
+func interpErrorToServiceErrorType(err error) error {
 	switch {
 	case errors.Is(err, ErrNonMathQuestion):
 		return service.ErrNonMathQuestion
 	case errors.Is(err, ErrUnsupportedOperation):
 		return service.ErrUnsupportedOperation
+	case errors.Is(err, ErrUnclosedGroup):
+		return service.ErrUnclosedGroup
+	case errors.Is(err, ErrUnopenedGroup):
+		return service.ErrUnopenedGroup
+	case errors.Is(err, ErrEmptyGroup):
+		return service.ErrEmptyGroup
 	case errors.Is(err, ErrInvalidSyntax):
 		return service.ErrInvalidSyntax
+	case errors.Is(err, ErrDivisionByZero):
+		return service.ErrDivisionByZero
+	case errors.Is(err, ErrOverflow):
+		return service.ErrOverflow
+	case errors.Is(err, ErrDomain):
+		return service.ErrDomain
+	case errors.Is(err, ErrDimensionMismatch):
+		return service.ErrDimensionMismatch
+	case errors.Is(err, ErrInvalidDate):
+		return service.ErrInvalidDate
+	case errors.Is(err, ErrUndefinedVariable):
+		return service.ErrUndefinedVariable
+	case errors.Is(err, ErrNoPreviousResult):
+		return service.ErrNoPreviousResult
 	default:
 		return err
 	}
@@ -1,7 +1,9 @@
 package interp_test
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/VitoNaychev/eval-web-service/interp"
 	"github.com/VitoNaychev/eval-web-service/testutil/assert"
@@ -10,72 +12,1227 @@ import (
 func TestInerpreter(t *testing.T) {
 	cases := []struct {
 		Name           string
-		Input          []interp.Token
-		ExpectedResult int
+		Input          interp.Node
+		ExpectedResult string
+		ExpectedError  error
 	}{
 		{
-			Name: "just a number",
-			Input: []interp.Token{
-				&interp.NumberToken{"8"},
-			},
-			ExpectedResult: 8,
+			Name:           "just a number",
+			Input:          numberNode("8"),
+			ExpectedResult: "8",
 		},
 		{
-			Name: "addition",
-			Input: []interp.Token{
-				&interp.NumberToken{"8"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"3"},
-			},
-			ExpectedResult: 11,
+			Name:           "addition",
+			Input:          binaryNode("plus", numberNode("8"), numberNode("3")),
+			ExpectedResult: "11",
 		},
 		{
-			Name: "subtraction",
-			Input: []interp.Token{
-				&interp.NumberToken{"8"},
-				&interp.OperandToken{"minus"},
-				&interp.NumberToken{"3"},
-			},
-			ExpectedResult: 5,
+			Name:           "subtraction",
+			Input:          binaryNode("minus", numberNode("8"), numberNode("3")),
+			ExpectedResult: "5",
 		},
 		{
-			Name: "multiplciation",
-			Input: []interp.Token{
-				&interp.NumberToken{"5"},
-				&interp.OperandToken{"multiplied by"},
-				&interp.NumberToken{"7"},
-			},
-			ExpectedResult: 35,
+			Name:           "multiplciation",
+			Input:          binaryNode("multiplied by", numberNode("5"), numberNode("7")),
+			ExpectedResult: "35",
+		},
+		{
+			Name:           "division",
+			Input:          binaryNode("divided by", numberNode("42"), numberNode("6")),
+			ExpectedResult: "7",
 		},
 		{
-			Name: "division",
-			Input: []interp.Token{
-				&interp.NumberToken{"42"},
-				&interp.OperandToken{"divided by"},
-				&interp.NumberToken{"6"},
+			Name: "nested operations",
+			Input: binaryNode("plus",
+				binaryNode("divided by", numberNode("42"), numberNode("6")),
+				binaryNode("multiplied by", numberNode("3"), numberNode("8")),
+			),
+			ExpectedResult: "31",
+		},
+		{
+			Name:           "signed number",
+			Input:          binaryNode("plus", numberNode("-3"), numberNode("5")),
+			ExpectedResult: "2",
+		},
+		{
+			Name: "negation",
+			Input: &interp.UnaryNode{
+				Operand: &interp.OperandToken{Value: "minus"},
+				Child:   binaryNode("plus", numberNode("3"), numberNode("5")),
 			},
-			ExpectedResult: 7,
-		},
-		{
-			Name: "sequence of operations",
-			Input: []interp.Token{
-				&interp.NumberToken{"42"},
-				&interp.OperandToken{"divided by"},
-				&interp.NumberToken{"6"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"3"},
-				&interp.OperandToken{"multiplied by"},
-				&interp.NumberToken{"8"},
+			ExpectedResult: "-8",
+		},
+		{
+			Name:           "error on division by zero",
+			Input:          binaryNode("divided by", numberNode("5"), numberNode("0")),
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrDivisionByZero,
+		},
+		{
+			Name: "error on division by zero in nested operation",
+			Input: binaryNode("plus",
+				numberNode("1"),
+				binaryNode("divided by", numberNode("5"), binaryNode("minus", numberNode("2"), numberNode("2"))),
+			),
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrDivisionByZero,
+		},
+		{
+			Name:           "exponentiation",
+			Input:          binaryNode("raised to the power of", numberNode("2"), numberNode("10")),
+			ExpectedResult: "1024",
+		},
+		{
+			Name:           "modulo",
+			Input:          binaryNode("modulo", numberNode("17"), numberNode("5")),
+			ExpectedResult: "2",
+		},
+		{
+			Name:           "squared",
+			Input:          unaryNode("squared", numberNode("-4")),
+			ExpectedResult: "16",
+		},
+		{
+			Name:           "cubed",
+			Input:          unaryNode("cubed", numberNode("3")),
+			ExpectedResult: "27",
+		},
+		{
+			Name:           "square root",
+			Input:          unaryNode("the square root of", numberNode("17")),
+			ExpectedResult: "4",
+		},
+		{
+			Name:           "error on modulo by zero",
+			Input:          binaryNode("modulo", numberNode("5"), numberNode("0")),
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrDivisionByZero,
+		},
+		{
+			Name:           "error on square root of a negative number",
+			Input:          unaryNode("the square root of", numberNode("-4")),
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrDomain,
+		},
+		{
+			Name:           "error on exponentiation overflow",
+			Input:          binaryNode("raised to the power of", numberNode("10"), numberNode("19")),
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			gotResult, gotError := interp.Interpret(test.Input, interp.Options{})
+
+			assert.Equal(t, resultString(gotResult), test.ExpectedResult)
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+		})
+	}
+}
+
+func TestInterpreterPrecedence(t *testing.T) {
+	tokens := []interp.Token{
+		&interp.QuestionToken{Value: "What is"},
+		&interp.NumberToken{Value: "2"},
+		&interp.OperandToken{Value: "plus"},
+		&interp.NumberToken{Value: "3"},
+		&interp.OperandToken{Value: "multiplied by"},
+		&interp.NumberToken{Value: "4"},
+		&interp.PunctuationToken{Value: "?"},
+	}
+
+	t.Run("applies multiplication before addition", func(t *testing.T) {
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, result.String(), "14")
+	})
+
+	t.Run("keeps left-to-right semantics when opted in", func(t *testing.T) {
+		tree, err := interp.ParseLeftToRight(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, result.String(), "20")
+	})
+}
+
+func TestInterpreterGroups(t *testing.T) {
+	t.Run("evaluates grouped subexpression first", func(t *testing.T) {
+		tokens, err := interp.Lex("What is (2 plus 3) multiplied by 4?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, result.String(), "20")
+	})
+}
+
+func TestInterpreterArithmetic(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          interp.Node
+		Arithmetic     interp.Arithmetic
+		ExpectedResult string
+		ExpectedError  error
+	}{
+		{
+			Name:           "integer division truncates",
+			Input:          binaryNode("divided by", numberNode("7"), numberNode("2")),
+			Arithmetic:     interp.ArithmeticInteger,
+			ExpectedResult: "3",
+		},
+		{
+			Name:           "float division",
+			Input:          binaryNode("divided by", numberNode("7"), numberNode("2")),
+			Arithmetic:     interp.ArithmeticFloat,
+			ExpectedResult: "3.5",
+		},
+		{
+			Name:           "decimal division",
+			Input:          binaryNode("divided by", numberNode("7"), numberNode("2")),
+			Arithmetic:     interp.ArithmeticDecimal,
+			ExpectedResult: "3.5",
+		},
+		{
+			Name:           "decimal division rounds non-terminating results",
+			Input:          binaryNode("divided by", numberNode("2"), numberNode("3")),
+			Arithmetic:     interp.ArithmeticDecimal,
+			ExpectedResult: "0.6666666666666666666666666666666667",
+		},
+		{
+			Name: "decimal keeps intermediate results exact",
+			Input: binaryNode("multiplied by",
+				binaryNode("divided by", numberNode("1"), numberNode("3")),
+				numberNode("3"),
+			),
+			Arithmetic:     interp.ArithmeticDecimal,
+			ExpectedResult: "1",
+		},
+		{
+			Name:           "rational division",
+			Input:          binaryNode("divided by", numberNode("7"), numberNode("2")),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "7/2",
+		},
+		{
+			Name: "rational result with integer value",
+			Input: binaryNode("plus",
+				binaryNode("divided by", numberNode("1"), numberNode("2")),
+				binaryNode("divided by", numberNode("-3"), numberNode("2")),
+			),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "-1",
+		},
+		{
+			Name:           "error on integer multiplication overflow",
+			Input:          binaryNode("multiplied by", numberNode("9999999999"), numberNode("9999999999")),
+			Arithmetic:     interp.ArithmeticInteger,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name:           "error on integer addition overflow",
+			Input:          binaryNode("plus", numberNode("9223372036854775807"), numberNode("1")),
+			Arithmetic:     interp.ArithmeticInteger,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name:           "error on integer subtraction overflow",
+			Input:          binaryNode("minus", numberNode("-9223372036854775808"), numberNode("1")),
+			Arithmetic:     interp.ArithmeticInteger,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name: "error on integer negation overflow",
+			Input: &interp.UnaryNode{
+				Operand: &interp.OperandToken{Value: "minus"},
+				Child:   numberNode("-9223372036854775808"),
 			},
-			ExpectedResult: 80,
+			Arithmetic:     interp.ArithmeticInteger,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name:           "error on out of range integer literal",
+			Input:          numberNode("99999999999999999999"),
+			Arithmetic:     interp.ArithmeticInteger,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name:           "big integer multiplication",
+			Input:          binaryNode("multiplied by", numberNode("9999999999"), numberNode("9999999999")),
+			Arithmetic:     interp.ArithmeticBigInteger,
+			ExpectedResult: "99999999980000000001",
+		},
+		{
+			Name:           "big integer literal",
+			Input:          binaryNode("plus", numberNode("99999999999999999999"), numberNode("1")),
+			Arithmetic:     interp.ArithmeticBigInteger,
+			ExpectedResult: "100000000000000000000",
 		},
+		{
+			Name:           "big integer division truncates",
+			Input:          binaryNode("divided by", numberNode("-7"), numberNode("2")),
+			Arithmetic:     interp.ArithmeticBigInteger,
+			ExpectedResult: "-3",
+		},
+		{
+			Name:           "float division by zero",
+			Input:          binaryNode("divided by", numberNode("7"), numberNode("0")),
+			Arithmetic:     interp.ArithmeticFloat,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrDivisionByZero,
+		},
+		{
+			Name:           "rational division by zero",
+			Input:          binaryNode("divided by", numberNode("7"), numberNode("0")),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrDivisionByZero,
+		},
+		{
+			Name:           "float fractional power",
+			Input:          binaryNode("raised to the power of", numberNode("4"), binaryNode("divided by", numberNode("1"), numberNode("2"))),
+			Arithmetic:     interp.ArithmeticFloat,
+			ExpectedResult: "2",
+		},
+		{
+			Name:           "float square root",
+			Input:          unaryNode("the square root of", numberNode("2")),
+			Arithmetic:     interp.ArithmeticFloat,
+			ExpectedResult: "1.4142135623730951",
+		},
+		{
+			Name:           "rational negative power",
+			Input:          binaryNode("raised to the power of", numberNode("2"), numberNode("-3")),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "1/8",
+		},
+		{
+			Name:           "rational modulo",
+			Input:          binaryNode("modulo", binaryNode("divided by", numberNode("7"), numberNode("2")), numberNode("2")),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "3/2",
+		},
+		{
+			Name:           "rational square root of a perfect square",
+			Input:          unaryNode("the square root of", binaryNode("divided by", numberNode("9"), numberNode("4"))),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "3/2",
+		},
+		{
+			Name:           "error on irrational rational square root",
+			Input:          unaryNode("the square root of", numberNode("2")),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrDomain,
+		},
+		{
+			Name:           "decimal square root",
+			Input:          unaryNode("the square root of", numberNode("2")),
+			Arithmetic:     interp.ArithmeticDecimal,
+			ExpectedResult: "1.4142135623730950488016887242096981",
+		},
+		{
+			Name:           "big integer power",
+			Input:          binaryNode("raised to the power of", numberNode("2"), numberNode("100")),
+			Arithmetic:     interp.ArithmeticBigInteger,
+			ExpectedResult: "1267650600228229401496703205376",
+		},
+		{
+			Name:           "error on fractional rational power",
+			Input:          binaryNode("raised to the power of", numberNode("4"), binaryNode("divided by", numberNode("1"), numberNode("2"))),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrDomain,
+		},
+		{
+			Name: "error on nested big integer powers",
+			Input: binaryNode("raised to the power of",
+				binaryNode("raised to the power of", numberNode("9"), numberNode("10000")),
+				numberNode("10000"),
+			),
+			Arithmetic:     interp.ArithmeticBigInteger,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name: "error on nested rational powers",
+			Input: binaryNode("raised to the power of",
+				binaryNode("raised to the power of", numberNode("9"), numberNode("10000")),
+				numberNode("10000"),
+			),
+			Arithmetic:     interp.ArithmeticRational,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name: "error on nested decimal powers",
+			Input: binaryNode("raised to the power of",
+				binaryNode("raised to the power of", numberNode("9"), numberNode("10000")),
+				numberNode("10000"),
+			),
+			Arithmetic:     interp.ArithmeticDecimal,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name: "error on chained big integer squares",
+			Input: unaryNode("squared", unaryNode("squared", unaryNode("squared", unaryNode("squared",
+				binaryNode("raised to the power of", numberNode("9"), numberNode("10000")),
+			)))),
+			Arithmetic:     interp.ArithmeticBigInteger,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+		{
+			Name: "error on big integer multiplication beyond the size limit",
+			Input: binaryNode("multiplied by",
+				binaryNode("raised to the power of", numberNode("2"), numberNode("100000")),
+				binaryNode("raised to the power of", numberNode("2"), numberNode("100000")),
+			),
+			Arithmetic:     interp.ArithmeticBigInteger,
+			ExpectedResult: "",
+			ExpectedError:  interp.ErrOverflow,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			opts := interp.Options{
+				Arithmetic: test.Arithmetic,
+			}
+			gotResult, gotError := interp.Interpret(test.Input, opts)
+
+			assert.Equal(t, resultString(gotResult), test.ExpectedResult)
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+		})
+	}
+}
+
+func resultString(value interp.Value) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+func numberNode(value string) interp.Node {
+	return &interp.NumberNode{
+		Number: &interp.NumberToken{Value: value},
+	}
+}
+
+func binaryNode(operand string, left, right interp.Node) interp.Node {
+	return &interp.BinaryNode{
+		Operand: &interp.OperandToken{Value: operand},
+		Left:    left,
+		Right:   right,
+	}
+}
+
+func unaryNode(operand string, child interp.Node) interp.Node {
+	return &interp.UnaryNode{
+		Operand: &interp.OperandToken{Value: operand},
+		Child:   child,
+	}
+}
+
+func TestInterpreterDiagnostics(t *testing.T) {
+	t.Run("points evaluation errors at the operand", func(t *testing.T) {
+		tokens, err := interp.Lex("What is 1 plus 5 divided by 0?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		_, gotError := interp.Interpret(tree, interp.Options{})
+		assert.ErrorIs(t, gotError, interp.ErrDivisionByZero)
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(gotError, &diagnostic), true)
+
+		assert.Equal(t, diagnostic.Position, interp.Position{Offset: 17, Length: 10})
+		assert.Equal(t, diagnostic.Text, "divided by")
+	})
+}
+
+func TestInterpreterVariables(t *testing.T) {
+	evaluate := func(input string, opts interp.Options) (interp.Value, error) {
+		tokens, err := interp.Lex(input)
+		if err != nil {
+			return nil, err
+		}
+
+		tree, err := interp.Parse(tokens)
+		if err != nil {
+			return nil, err
+		}
+
+		return interp.Interpret(tree, opts)
+	}
+
+	t.Run("reads variables assigned in earlier statements", func(t *testing.T) {
+		environment := interp.Environment{}
+
+		result, err := evaluate("Let x be 5.", interp.Options{Environment: environment})
+		assert.RequireNoError(t, err)
+		assert.Equal(t, result.String(), "5")
+
+		result, err = evaluate("What is x multiplied by 3?", interp.Options{Environment: environment})
+		assert.RequireNoError(t, err)
+		assert.Equal(t, result.String(), "15")
+	})
+
+	t.Run("reassigns a variable from its own value", func(t *testing.T) {
+		environment := interp.Environment{"x": "5"}
+
+		_, err := evaluate("Let x be x plus 1.", interp.Options{Environment: environment})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, environment, interp.Environment{"x": "6"})
+	})
+
+	t.Run("reads variables in the current arithmetic", func(t *testing.T) {
+		environment := interp.Environment{"x": "7/2"}
+
+		result, err := evaluate("What is x multiplied by 2?", interp.Options{
+			Arithmetic:  interp.ArithmeticRational,
+			Environment: environment,
+		})
+		assert.RequireNoError(t, err)
+		assert.Equal(t, result.String(), "7")
+
+		_, err = evaluate("What is x multiplied by 2?", interp.Options{Environment: environment})
+		assert.ErrorIs(t, err, interp.ErrDomain)
+	})
+
+	t.Run("error on undefined variable", func(t *testing.T) {
+		_, err := evaluate("What is y plus 1?", interp.Options{Environment: interp.Environment{}})
+		assert.ErrorIs(t, err, interp.ErrUndefinedVariable)
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(err, &diagnostic), true)
+		assert.Equal(t, diagnostic.Text, "y")
+	})
+}
+
+func TestInterpreterPreviousResult(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		Previous       string
+		Arithmetic     interp.Arithmetic
+		ExpectedResult string
+		ExpectedError  error
+	}{
+		{"the result", "What is the result multiplied by 2?", "8", interp.ArithmeticInteger, "16", nil},
+		{"that", "What is that squared?", "-3", interp.ArithmeticInteger, "9", nil},
+		{"assigned to a variable", "Let x be the result.", "5", interp.ArithmeticInteger, "5", nil},
+		{"in the current arithmetic", "What is the result plus 1?", "7/2", interp.ArithmeticRational, "9/2", nil},
+		{"error on no previous result", "What is the result plus 1?", "", interp.ArithmeticInteger, "", interp.ErrNoPreviousResult},
 	}
 
 	for _, test := range cases {
 		t.Run(test.Name, func(t *testing.T) {
-			gotResult := interp.Interpret(test.Input)
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
 
-			assert.Equal(t, gotResult, test.ExpectedResult)
+			result, gotError := interp.Interpret(tree, interp.Options{
+				Arithmetic: test.Arithmetic,
+				Previous:   test.Previous,
+			})
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+
+			if test.ExpectedError == nil {
+				assert.Equal(t, result.String(), test.ExpectedResult)
+			}
 		})
 	}
+
+	t.Run("points the error at the reference", func(t *testing.T) {
+		tokens, err := interp.Lex("What is 1 plus that?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		_, gotError := interp.Interpret(tree, interp.Options{})
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(gotError, &diagnostic), true)
+		assert.Equal(t, diagnostic.Position, interp.Position{Offset: 15, Length: 4})
+	})
+}
+
+func TestInterpreterFunctions(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		Arithmetic     interp.Arithmetic
+		ExpectedResult string
+	}{
+		{"sum", "What is the sum of 1, 2, and 3?", interp.ArithmeticInteger, "6"},
+		{"sum of number words", "What is the sum of three and five?", interp.ArithmeticInteger, "8"},
+		{"product", "What is the product of 2, 3 and 4?", interp.ArithmeticInteger, "24"},
+		{"average", "What is the average of 1, 2 and 4?", interp.ArithmeticRational, "7/3"},
+		{"average truncates integers", "What is the average of 1, 2 and 4?", interp.ArithmeticInteger, "2"},
+		{"minimum", "What is the minimum of 4, minus 2 and 7?", interp.ArithmeticInteger, "-2"},
+		{"maximum", "What is the maximum of 4, 9 and 7?", interp.ArithmeticInteger, "9"},
+		{"median of an odd count", "What is the median of 9, 1 and 4?", interp.ArithmeticInteger, "4"},
+		{"median of an even count", "What is the median of 9, 1, 4 and 2?", interp.ArithmeticRational, "3"},
+		{"single argument", "What is the sum of 5?", interp.ArithmeticInteger, "5"},
+		{"applies infix operands to the result", "What is the sum of 1 and 2 multiplied by 3?", interp.ArithmeticInteger, "9"},
+		{"nested functions", "What is the maximum of 1 and the minimum of 5 and 3?", interp.ArithmeticInteger, "3"},
+		{"postfix arguments", "What is the sum of 2 squared and 3?", interp.ArithmeticInteger, "7"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{Arithmetic: test.Arithmetic})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	t.Run("points evaluation errors at the function", func(t *testing.T) {
+		tokens, err := interp.Lex("What is the sum of 9223372036854775807 and 1?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		_, gotError := interp.Interpret(tree, interp.Options{})
+		assert.ErrorIs(t, gotError, interp.ErrOverflow)
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(gotError, &diagnostic), true)
+		assert.Equal(t, diagnostic.Position, interp.Position{Offset: 8, Length: 10})
+		assert.Equal(t, diagnostic.Text, "the sum of")
+	})
+}
+
+func TestInterpreterYesNoQuestions(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		ExpectedResult string
+	}{
+		{"greater than", "Is 5 greater than 3?", "true"},
+		{"less than", "Is 5 less than 3?", "false"},
+		{"equal to", "Is 2 squared equal to 4?", "true"},
+		{"at least", "Is 3 at least 3?", "true"},
+		{"at most", "Is 4 at most 3?", "false"},
+		{"and", "Is 1 less than 2 and 2 less than 1?", "false"},
+		{"or", "Is 1 less than 2 or 2 less than 1?", "true"},
+		{"and before or", "Is 1 less than 2 or 2 less than 1 and 3 less than 2?", "true"},
+		{"functions", "Is the maximum of 1 and 7 greater than the sum of 2 and 3?", "true"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result, interp.Value(interp.Boolean(test.ExpectedResult == "true")))
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	t.Run("compares in the current arithmetic", func(t *testing.T) {
+		tokens, err := interp.Lex("Is 7 divided by 2 greater than 3?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{})
+		assert.RequireNoError(t, err)
+		assert.Equal(t, result.String(), "false")
+
+		result, err = interp.Interpret(tree, interp.Options{Arithmetic: interp.ArithmeticRational})
+		assert.RequireNoError(t, err)
+		assert.Equal(t, result.String(), "true")
+	})
+
+	t.Run("doesn't evaluate a comparison the answer doesn't depend on", func(t *testing.T) {
+		tokens, err := interp.Lex("Is 1 less than 2 or 1 divided by 0 less than 2?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{})
+		assert.RequireNoError(t, err)
+		assert.Equal(t, result.String(), "true")
+	})
+
+	t.Run("reports errors of the compared expressions", func(t *testing.T) {
+		tokens, err := interp.Lex("Is 1 divided by 0 less than 2?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		_, gotError := interp.Interpret(tree, interp.Options{})
+		assert.ErrorIs(t, gotError, interp.ErrDivisionByZero)
+	})
+}
+
+func TestInterpreterPercentages(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		Arithmetic     interp.Arithmetic
+		ExpectedResult string
+	}{
+		{"percent of", "What is 15 percent of 200?", interp.ArithmeticInteger, "30"},
+		{"percent of keeps fractions", "What is 15 percent of 10?", interp.ArithmeticDecimal, "1.5"},
+		{"percent of floats", "What is 15 percent of 10?", interp.ArithmeticFloat, "1.5"},
+		{"percent", "What is 50 percent?", interp.ArithmeticDecimal, "0.5"},
+		{"percent as a fraction", "What is 25 percent?", interp.ArithmeticRational, "1/4"},
+		{"increased by", "What is 200 increased by 10 percent?", interp.ArithmeticInteger, "220"},
+		{"decreased by", "What is 200 decreased by 10 percent?", interp.ArithmeticInteger, "180"},
+		{"increased by an amount", "What is 200 increased by 10?", interp.ArithmeticInteger, "210"},
+		{"plus a percentage", "What is 80 plus 25 percent?", interp.ArithmeticInteger, "100"},
+		{"relative to the left operand", "What is 2 multiplied by 50 plus 10 percent?", interp.ArithmeticInteger, "110"},
+		{"percentage of a group", "What is (1 plus 1) percent of 50?", interp.ArithmeticInteger, "1"},
+		{"multiplied by a percentage", "What is 40 multiplied by 25 percent?", interp.ArithmeticDecimal, "10"},
+		{"multiplied by a percentage in integer arithmetic", "What is 200 multiplied by 10 percent?", interp.ArithmeticInteger, "20"},
+		{"multiplied by a percentage in big integer arithmetic", "What is 200 multiplied by 10 percent?", interp.ArithmeticBigInteger, "20"},
+		{"multiplied by a percentage in float arithmetic", "What is 200 multiplied by 10 percent?", interp.ArithmeticFloat, "20"},
+		{"percent of truncates in integer arithmetic", "What is 15 percent of 10?", interp.ArithmeticInteger, "1"},
+		{"percent of truncates in big integer arithmetic", "What is 7 percent of 15?", interp.ArithmeticBigInteger, "1"},
+		{"percent in integer arithmetic", "What is 50 percent?", interp.ArithmeticInteger, "0"},
+		{"percent plus a number in integer arithmetic", "What is 10 percent plus 5?", interp.ArithmeticInteger, "5"},
+		{"percent in float arithmetic", "What is 10 percent?", interp.ArithmeticFloat, "0.1"},
+		{"exact fraction combined further", "What is 15 percent of 10 multiplied by 2?", interp.ArithmeticInteger, "3"},
+		{"whole result settles in the arithmetic", "What is (200 multiplied by 10 percent) divided by 3?", interp.ArithmeticInteger, "6"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{Arithmetic: test.Arithmetic})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	t.Run("compares percentages exactly", func(t *testing.T) {
+		tokens, err := interp.Lex("Is 10 percent less than 1?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, result, interp.Value(interp.Boolean(true)))
+	})
+
+	t.Run("reads back percentages stored in integer arithmetic", func(t *testing.T) {
+		environment := interp.Environment{}
+		previous := ""
+		for _, statement := range []struct {
+			Input          string
+			ExpectedResult string
+		}{
+			{"Let x be 50 percent.", "0"},
+			{"What is x plus 1?", "1"},
+			{"What is 7 percent of 15?", "1"},
+			{"What is the result plus 1?", "2"},
+		} {
+			tokens, err := interp.Lex(statement.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{
+				Arithmetic:  interp.ArithmeticInteger,
+				Environment: environment,
+				Previous:    previous,
+			})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), statement.ExpectedResult)
+			previous = result.String()
+		}
+	})
+}
+
+func TestInterpreterUnits(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		Arithmetic     interp.Arithmetic
+		ExpectedResult string
+	}{
+		{"conversion", "What is 5 kilometers plus 300 meters in meters?", interp.ArithmeticInteger, "5300 m"},
+		{"adds in the smaller unit", "What is 1 hour plus 30 minutes?", interp.ArithmeticInteger, "90 min"},
+		{"converts to a larger unit", "What is 5 kilometers plus 300 meters in kilometers?", interp.ArithmeticDecimal, "5.3 km"},
+		{"scales by a number", "What is 3 kilograms multiplied by 2?", interp.ArithmeticInteger, "6 kg"},
+		{"ratio of two quantities", "What is 1 kilometer divided by 250 meters?", interp.ArithmeticInteger, "4"},
+		{"data size", "What is 2 kibibytes in bytes?", interp.ArithmeticInteger, "2048 B"},
+		{"function", "What is the maximum of 1 kilometer and 900 meters?", interp.ArithmeticInteger, "1 km"},
+		{"percentage of a quantity", "What is 200 grams increased by 10 percent?", interp.ArithmeticInteger, "220 g"},
+		{"comparison", "Is 1 mile greater than 1 kilometer?", interp.ArithmeticInteger, "true"},
+		{"assignment", "Let d be 2 minutes in seconds.", interp.ArithmeticInteger, "120 s"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{Arithmetic: test.Arithmetic})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	errorCases := []struct {
+		Name          string
+		Input         string
+		ExpectedText  string
+		ExpectedError error
+	}{
+		{"adding different dimensions", "What is 5 kilometers plus 3 seconds?", "plus", interp.ErrDimensionMismatch},
+		{"adding a plain number", "What is 5 meters plus 3?", "plus", interp.ErrDimensionMismatch},
+		{"converting to another dimension", "What is 5 meters in grams?", "grams", interp.ErrDimensionMismatch},
+		{"converting a plain number", "What is 5 in meters?", "meters", interp.ErrDimensionMismatch},
+		{"multiplying two quantities", "What is 5 meters multiplied by 2 meters?", "multiplied by", interp.ErrDimensionMismatch},
+		{"comparing different dimensions", "Is 5 meters less than 3 hours?", "less than", interp.ErrDimensionMismatch},
+		{"fraction in integer arithmetic", "What is 300 meters in kilometers?", "kilometers", interp.ErrDomain},
+	}
+
+	for _, test := range errorCases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			_, gotError := interp.Interpret(tree, interp.Options{})
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+
+			var diagnostic *interp.Diagnostic
+			assert.Equal(t, errors.As(gotError, &diagnostic), true)
+			assert.Equal(t, diagnostic.Text, test.ExpectedText)
+		})
+	}
+
+	t.Run("reads stored quantities", func(t *testing.T) {
+		tokens, err := interp.Lex("What is x plus the result in meters?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{
+			Environment: interp.Environment{"x": "2 km"},
+			Previous:    "500 m",
+		})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, result.String(), "2500 m")
+	})
+}
+
+func TestInterpreterDecimalLiterals(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		Arithmetic     interp.Arithmetic
+		ExpectedResult string
+	}{
+		{"float", "What is 0.1 plus 0.2?", interp.ArithmeticFloat, "0.30000000000000004"},
+		{"decimal", "What is 0.1 plus 0.2?", interp.ArithmeticDecimal, "0.3"},
+		{"rational", "What is 0.5 plus 1?", interp.ArithmeticRational, "3/2"},
+		{"fractional power", "What is 4 raised to the power of 0.5?", interp.ArithmeticFloat, "2"},
+		{"whole number in integer arithmetic", "What is 2.0 plus 3?", interp.ArithmeticInteger, "5"},
+		{"whole number in big integer arithmetic", "What is 2.00 multiplied by 3.0?", interp.ArithmeticBigInteger, "6"},
+		{"ends a sentence", "What is 1.5 plus 1.", interp.ArithmeticDecimal, "2.5"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{Arithmetic: test.Arithmetic})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	t.Run("error on a fraction in integer arithmetic", func(t *testing.T) {
+		tokens, err := interp.Lex("What is 1.5 plus 2?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		_, gotError := interp.Interpret(tree, interp.Options{Arithmetic: interp.ArithmeticInteger})
+		assert.ErrorIs(t, gotError, interp.ErrDomain)
+	})
+}
+
+func TestInterpreterNumeralSystems(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		Arithmetic     interp.Arithmetic
+		ExpectedResult string
+	}{
+		{"hexadecimal and binary literals", "What is 0x1F plus 0b101?", interp.ArithmeticInteger, "36"},
+		{"roman literals", "What is XIV multiplied by III?", interp.ArithmeticInteger, "42"},
+		{"in hexadecimal", "What is 255 in hexadecimal?", interp.ArithmeticInteger, "0xff"},
+		{"in octal", "What is 64 in octal?", interp.ArithmeticInteger, "0o100"},
+		{"in binary", "What is 0x1F plus 0b101 in binary?", interp.ArithmeticInteger, "0b100100"},
+		{"in roman numerals", "What is XIV multiplied by III in Roman numerals?", interp.ArithmeticInteger, "XLII"},
+		{"in decimal", "What is 0xff in decimal?", interp.ArithmeticInteger, "255"},
+		{"negative number", "What is minus 31 in hexadecimal?", interp.ArithmeticInteger, "-0x1f"},
+		{"whole result in decimal arithmetic", "What is 7 divided by 7 in binary?", interp.ArithmeticDecimal, "0b1"},
+		{"big integer", "What is 2 raised to the power of 64 in hexadecimal?", interp.ArithmeticBigInteger, "0x10000000000000000"},
+		{"assignment", "Let x be 10 in hexadecimal.", interp.ArithmeticInteger, "0xa"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{Arithmetic: test.Arithmetic})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	errorCases := []struct {
+		Name          string
+		Input         string
+		Arithmetic    interp.Arithmetic
+		ExpectedError error
+	}{
+		{"fraction", "What is 7 divided by 2 in hexadecimal?", interp.ArithmeticRational, interp.ErrDomain},
+		{"roman zero", "What is 0 in Roman numerals?", interp.ArithmeticInteger, interp.ErrDomain},
+		{"roman out of range", "What is 4000 in Roman numerals?", interp.ArithmeticInteger, interp.ErrDomain},
+		{"quantity", "What is 5 meters in hexadecimal?", interp.ArithmeticInteger, interp.ErrDimensionMismatch},
+		{"literal out of range", "What is 0x10000000000000000?", interp.ArithmeticInteger, interp.ErrOverflow},
+	}
+
+	for _, test := range errorCases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			_, gotError := interp.Interpret(tree, interp.Options{Arithmetic: test.Arithmetic})
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+		})
+	}
+
+	t.Run("error on an unbound single-letter roman numeral", func(t *testing.T) {
+		for _, input := range []string{"What is x plus 1?", "What is X plus V?", "What is I plus I?"} {
+			tokens, err := interp.Lex(input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			_, gotError := interp.Interpret(tree, interp.Options{Environment: interp.Environment{}})
+			assert.ErrorIs(t, gotError, interp.ErrUndefinedVariable)
+		}
+	})
+
+	t.Run("reads stored numbers in other numeral systems", func(t *testing.T) {
+		tokens, err := interp.Lex("What is x plus the result?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{
+			Environment: interp.Environment{"x": "0xff"},
+			Previous:    "XLII",
+		})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, result.String(), "297")
+	})
+}
+
+func TestInterpreterDates(t *testing.T) {
+	sofia, err := time.LoadLocation("Europe/Sofia")
+	assert.RequireNoError(t, err)
+
+	cases := []struct {
+		Name           string
+		Input          string
+		Location       *time.Location
+		ExpectedResult string
+	}{
+		{"adds days", "What is 2024-03-01 plus 45 days?", nil, "2024-04-15"},
+		{"adds days before a date", "What is 45 days plus 2024-03-01?", nil, "2024-04-15"},
+		{"subtracts weeks", "What is 2024-03-01 minus 2 weeks?", nil, "2024-02-16"},
+		{"adds elapsed time", "What is 2024-03-01T10:00 plus 90 minutes?", nil, "2024-03-01T11:30:00Z"},
+		{"difference of dates", "What is 2024-03-01 minus 2024-01-01?", nil, "60 d"},
+		{"difference in the largest exact unit", "What is 2024-03-01T10:30 minus 2024-03-01?", nil, "630 min"},
+		{"converts a difference", "What is 2024-03-08 minus 2024-03-01 in hours?", nil, "168 h"},
+		{"durations", "What is 3 hours minus 25 minutes?", nil, "155 min"},
+		{"comparison", "Is 2024-03-01 greater than 2024-01-01?", nil, "true"},
+		{"function", "What is the minimum of 2024-03-01 and 2024-01-01?", nil, "2024-01-01"},
+		{"reads and writes in the location", "What is 2024-03-01 plus 10 hours?", sofia, "2024-03-01T10:00:00+02:00"},
+		{"keeps the time of day across daylight saving", "What is 2024-03-30T12:00 plus 1 day?", sofia, "2024-03-31T12:00:00+03:00"},
+		{"elapsed time across daylight saving", "What is 2024-03-30T12:00 plus 24 hours?", sofia, "2024-03-31T13:00:00+03:00"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{Location: test.Location})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	errorCases := []struct {
+		Name          string
+		Input         string
+		ExpectedText  string
+		ExpectedError error
+	}{
+		{"adding two dates", "What is 2024-03-01 plus 2024-01-01?", "plus", interp.ErrDimensionMismatch},
+		{"adding a plain number", "What is 2024-03-01 plus 5?", "plus", interp.ErrDimensionMismatch},
+		{"adding another dimension", "What is 2024-03-01 plus 5 meters?", "plus", interp.ErrDimensionMismatch},
+		{"multiplying a date", "What is 2024-03-01 multiplied by 2?", "multiplied by", interp.ErrDimensionMismatch},
+		{"subtracting a date from a duration", "What is 5 days minus 2024-03-01?", "minus", interp.ErrDimensionMismatch},
+		{"measuring a date in a unit", "What is 2024-03-01 days?", "days", interp.ErrDimensionMismatch},
+		{"comparing a date with a number", "Is 2024-03-01 greater than 5?", "greater than", interp.ErrDimensionMismatch},
+		{"date that doesn't exist", "What is 2024-02-30 plus 1 day?", "2024-02-30", interp.ErrInvalidDate},
+		{"date out of range", "What is 9999-12-31 plus 1 day?", "plus", interp.ErrOverflow},
+	}
+
+	for _, test := range errorCases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			_, gotError := interp.Interpret(tree, interp.Options{})
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+
+			var diagnostic *interp.Diagnostic
+			assert.Equal(t, errors.As(gotError, &diagnostic), true)
+			assert.Equal(t, diagnostic.Text, test.ExpectedText)
+		})
+	}
+
+	t.Run("reads stored dates", func(t *testing.T) {
+		tokens, err := interp.Lex("What is the result minus x?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := interp.Interpret(tree, interp.Options{
+			Environment: interp.Environment{"x": "2024-03-01"},
+			Previous:    "2024-03-01T12:00:00+02:00",
+			Location:    sofia,
+		})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, result.String(), "12 h")
+	})
+}
+
+func TestAnswer(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		Arithmetic     interp.Arithmetic
+		Format         interp.AnswerFormat
+		ExpectedAnswer string
+	}{
+		{"math question", "What is 5 plus 3?", interp.ArithmeticInteger, interp.AnswerSentence, "5 plus 3 is 8."},
+		{"spelled out", "What is 5 plus 3?", interp.ArithmeticInteger, interp.AnswerWords, "Five plus three is eight."},
+		{"large numbers", "What is 1005 multiplied by 1000?", interp.ArithmeticInteger, interp.AnswerWords, "One thousand and five multiplied by one thousand is one million five thousand."},
+		{"hundreds", "What is 142 minus 100?", interp.ArithmeticInteger, interp.AnswerWords, "One hundred and forty-two minus one hundred is forty-two."},
+		{"negative result", "What is 3 minus 5?", interp.ArithmeticInteger, interp.AnswerWords, "Three minus five is minus two."},
+		{"decimal result", "What is 3 divided by 2?", interp.ArithmeticDecimal, interp.AnswerWords, "Three divided by two is one point five."},
+		{"rational result", "What is 7 divided by 2?", interp.ArithmeticRational, interp.AnswerWords, "Seven divided by two is seven over two."},
+		{"groups", "What is (2 plus 3) multiplied by 4?", interp.ArithmeticInteger, interp.AnswerSentence, "(2 plus 3) multiplied by 4 is 20."},
+		{"function", "What is the sum of 1, 2 and 3?", interp.ArithmeticInteger, interp.AnswerSentence, "The sum of 1, 2 and 3 is 6."},
+		{"quantity", "What is 2 hours in minutes?", interp.ArithmeticInteger, interp.AnswerWords, "Two hours in minutes is one hundred and twenty minutes."},
+		{"quantity in digits", "What is 2 hours in minutes?", interp.ArithmeticInteger, interp.AnswerSentence, "2 hours in minutes is 120 min."},
+		{"single unit", "What is 5300 meters minus 5299 meters?", interp.ArithmeticInteger, interp.AnswerWords, "Five thousand three hundred meters minus five thousand two hundred and ninety-nine meters is one meter."},
+		{"irregular plural", "What is 24 inches in feet?", interp.ArithmeticInteger, interp.AnswerWords, "Twenty-four inches in feet is two feet."},
+		{"beyond the largest scale", "What is 1000000 multiplied by 1000000000000?", interp.ArithmeticBigInteger, interp.AnswerWords, "One million multiplied by one trillion is one million trillion."},
+		{"counted in the largest scale", "What is 1000000000000000 plus 1?", interp.ArithmeticBigInteger, interp.AnswerWords, "One thousand trillion plus one is one thousand trillion and one."},
+		{"numeral system", "What is 255 in hexadecimal?", interp.ArithmeticInteger, interp.AnswerWords, "Two hundred and fifty-five in hexadecimal is 0xff."},
+		{"written as in the input", "What is 5  PLUS 3?", interp.ArithmeticInteger, interp.AnswerSentence, "5 PLUS 3 is 8."},
+		{"date as in the input", "What is 2024-03-01T10:00 plus one hour?", interp.ArithmeticInteger, interp.AnswerSentence, "2024-03-01T10:00 plus one hour is 2024-03-01T11:00:00Z."},
+		{"large float", "What is 99999999980000000000 plus 1?", interp.ArithmeticFloat, interp.AnswerWords, "Ninety-nine million nine hundred and ninety-nine thousand nine hundred and ninety-nine trillion nine hundred and eighty billion plus one is ninety-nine million nine hundred and ninety-nine thousand nine hundred and ninety-nine trillion nine hundred and eighty billion."},
+		{"small float", "What is 1 divided by 10000000?", interp.ArithmeticFloat, interp.AnswerWords, "One divided by ten million is zero point zero zero zero zero zero zero one."},
+		{"and after a scale", "What is 2000 plus 5?", interp.ArithmeticInteger, interp.AnswerWords, "Two thousand plus five is two thousand and five."},
+		{"assignment", "Let x be 5 plus 3.", interp.ArithmeticInteger, interp.AnswerSentence, "x is 8."},
+		{"variable", "What is x multiplied by 2?", interp.ArithmeticInteger, interp.AnswerSentence, "x multiplied by 2 is 10."},
+		{"yes", "Is 5 greater than 3?", interp.ArithmeticInteger, interp.AnswerSentence, "Yes, 5 is greater than 3."},
+		{"no", "Is 5 less than 3 or 2 equal to 3?", interp.ArithmeticInteger, interp.AnswerWords, "No, it is not true that five is less than three or two equal to three."},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := interp.Interpret(tree, interp.Options{
+				Arithmetic:  test.Arithmetic,
+				Environment: interp.Environment{"x": "5"},
+			})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, interp.Answer(test.Input, tokens, result, test.Format), test.ExpectedAnswer)
+		})
+	}
+}
+
+func TestInterpreterTrace(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Input         string
+		ExpectedSteps []string
+	}{
+		{"binary operations", "What is (2 plus 3) multiplied by 4?", []string{"2 plus 3 = 5", "5 multiplied by 4 = 20"}},
+		{"prefix operator", "What is the square root of 16 plus 1?", []string{"the square root of 16 = 4", "4 plus 1 = 5"}},
+		{"postfix operator", "What is 3 squared?", []string{"3 squared = 9"}},
+		{"function", "What is the sum of 1, 2 and 3?", []string{"the sum of 1, 2, 3 = 6"}},
+		{"relative percentage", "What is 200 increased by 10 percent?", []string{"10 percent = 20", "200 increased by 20 = 220"}},
+		{"conversion", "What is 2 hours in minutes?", []string{"2 h in minutes = 120 min"}},
+		{"numeral system", "What is 255 in hexadecimal?", []string{"255 in hexadecimal = 0xff"}},
+		{"comparison", "Is 5 greater than 3?", []string{"5 greater than 3 = true"}},
+		{"no operations", "What is 5?", nil},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := interp.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			trace := &interp.Trace{}
+			_, err = interp.Interpret(tree, interp.Options{
+				Arithmetic: interp.ArithmeticInteger,
+				Trace:      trace,
+			})
+			assert.RequireNoError(t, err)
+
+			var steps []string
+			for _, step := range trace.Steps {
+				steps = append(steps, step.String())
+			}
+			assert.Equal(t, steps, test.ExpectedSteps)
+		})
+	}
+
+	t.Run("locates the operator of each step", func(t *testing.T) {
+		input := "What is 2 plus 3 in hexadecimal?"
+		tokens, err := interp.Lex(input)
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		trace := &interp.Trace{}
+		_, err = interp.Interpret(tree, interp.Options{Trace: trace})
+		assert.RequireNoError(t, err)
+
+		var operators []string
+		for _, step := range trace.Steps {
+			operators = append(operators, input[step.Position.Offset:step.Position.Offset+step.Position.Length])
+		}
+		assert.Equal(t, operators, []string{"plus", "in hexadecimal"})
+	})
+
+	t.Run("records no step for a failed operation", func(t *testing.T) {
+		tokens, err := interp.Lex("What is 2 plus 3 divided by 0?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		trace := &interp.Trace{}
+		_, err = interp.Interpret(tree, interp.Options{Trace: trace})
+		assert.ErrorIs(t, err, interp.ErrDivisionByZero)
+
+		assert.Equal(t, len(trace.Steps), 0)
+	})
 }
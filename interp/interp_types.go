@@ -0,0 +1,26 @@
+package interp
+
+type InterpreterError struct {
+	msg string
+}
+
+func NewInterpreterError(msg string) *InterpreterError {
+	return &InterpreterError{
+		msg: msg,
+	}
+}
+
+func (i *InterpreterError) Error() string {
+	return i.msg
+}
+
+var (
+	ErrDivisionByZero    = NewInterpreterError("division by zero")
+	ErrOverflow          = NewInterpreterError("overflow")
+	ErrDomain            = NewInterpreterError("math domain error")
+	ErrDimensionMismatch = NewInterpreterError("dimension mismatch")
+	ErrInvalidDate       = NewInterpreterError("invalid date")
+
+	ErrUndefinedVariable = NewInterpreterError("undefined variable")
+	ErrNoPreviousResult  = NewInterpreterError("no previous result")
+)
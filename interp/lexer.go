@@ -1,29 +1,23 @@
 package interp
 
-import (
-	"fmt"
-	"regexp"
-
-	"github.com/VitoNaychev/eval-web-service/sm"
-)
+import "github.com/VitoNaychev/eval-web-service/sm"
 
+// Lex normalises the input and splits it into tokens, recognising the
+// built-in operators.
 func Lex(input string) ([]Token, error) {
-	ctx := LexerContext{
-		Input:                  input,
-		FinderToConstructorMap: newFinderToConstructorMap(),
-
-		Tokens: []Token{},
-	}
-	lexer := sm.New(sm.State(stateLexerTokenise), lexerDeltas, &ctx)
+	return defaultOperators.Lex(input)
+}
 
-	validPattern := fmt.Sprintf("%s|%s|%s|%s",
-		QuestionTokenPattern, NumberTokenPattern, OperandTokenPattern, PunctuationTokenPattern)
-	validRegex := regexp.MustCompile(validPattern)
+// Lex normalises the input and splits it into tokens, recognising the
+// operators held by the registry.
+func (o *OperatorRegistry) Lex(input string) ([]Token, error) {
+	ctx := NewLexerContext(input, o)
+	lexer := sm.New(sm.State(stateLexerTokenise), lexerDeltas, ctx)
 
 	for len(ctx.Input) > 0 {
 		var err error
 
-		if validRegex.MatchString(ctx.Input) {
+		if _, _, ok := ctx.Registry.Match(ctx.Input); ok {
 			err = lexer.Exec(sm.Event(eventLexerSupportedToken))
 		} else {
 			err = lexer.Exec(sm.Event(eventLexerUnsupportedToken))
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/VitoNaychev/eval-web-service/sm"
 )
@@ -29,25 +30,28 @@ const (
 func tokeniseCallback(delta sm.Delta, ctx sm.Context) error {
 	lexerCtx := ctx.(*LexerContext)
 
-	for finder, tokenConstructor := range lexerCtx.FinderToConstructorMap {
-		if value := finder.FindString(lexerCtx.Input); value != "" {
-			lexerCtx.Tokens = append(lexerCtx.Tokens, tokenConstructor(value))
+	constructor, value, ok := lexerCtx.Registry.Match(lexerCtx.Input)
+	if !ok {
+		return errors.New("event cannot be executed, invalid context")
+	}
 
-			lexerCtx.Input = lexerCtx.Input[len(value):]
-			lexerCtx.Input = strings.TrimSpace(lexerCtx.Input)
+	pos := lexerCtx.Source.position(lexerCtx.Input, len(value))
+	lexerCtx.Tokens = append(lexerCtx.Tokens, constructor(value, pos))
 
-			return nil
-		}
-	}
+	lexerCtx.Input = lexerCtx.Input[len(value):]
+	lexerCtx.Input = strings.TrimSpace(lexerCtx.Input)
 
-	return errors.New("event cannot be executed, invalid context")
+	return nil
 }
 
+// hasMathQuestion reports whether the input is a statement the interpreter
+// understands, i.e. a math question, a yes/no question or an assignment.
 func hasMathQuestion(delta sm.Delta, ctx sm.Context) (bool, error) {
 	lexerCtx := ctx.(*LexerContext)
 
 	for _, token := range lexerCtx.Tokens {
-		if _, ok := token.(*QuestionToken); ok {
+		switch token.(type) {
+		case *QuestionToken, *YesNoQuestionToken, *AssignmentToken:
 			return true, nil
 		}
 	}
@@ -56,7 +60,10 @@ func hasMathQuestion(delta sm.Delta, ctx sm.Context) (bool, error) {
 }
 
 func unsupportedOperationCallback(delta sm.Delta, ctx sm.Context) error {
-	return ErrUnsupportedOperation
+	lexerCtx := ctx.(*LexerContext)
+
+	pos := unsupportedWordPosition(lexerCtx)
+	return unsupportedWordDiagnostic(lexerCtx, ErrUnsupportedOperation, pos, nil)
 }
 
 func hasNotMathQuestion(delta sm.Delta, ctx sm.Context) (bool, error) {
@@ -64,8 +71,47 @@ func hasNotMathQuestion(delta sm.Delta, ctx sm.Context) (bool, error) {
 	return !hasMathQuestion, err
 }
 
+// nonMathQuestionCallback points at the word that can't be lexed or, when
+// the whole input was lexed, at the first token in place of the question.
 func nonMathQuestionCallback(delta sm.Delta, ctx sm.Context) error {
-	return ErrNonMathQuestion
+	lexerCtx := ctx.(*LexerContext)
+
+	expected := []string{"question"}
+	if len(lexerCtx.Input) > 0 {
+		pos := unsupportedWordPosition(lexerCtx)
+		return unsupportedWordDiagnostic(lexerCtx, ErrNonMathQuestion, pos, expected)
+	}
+
+	var pos Position
+	if len(lexerCtx.Tokens) > 0 {
+		pos = lexerCtx.Tokens[0].GetPosition()
+	}
+
+	return NewDiagnostic(ErrNonMathQuestion, pos, lexerCtx.Source.text(pos), expected)
+}
+
+// unsupportedWordDiagnostic points at a word the lexer can't read and
+// suggests the known words closest to it.
+func unsupportedWordDiagnostic(lexerCtx *LexerContext, err error, pos Position, expected []string) *Diagnostic {
+	word := unsupportedWordRegex.FindString(lexerCtx.Input)
+
+	diagnostic := NewDiagnostic(err, pos, lexerCtx.Source.text(pos), expected)
+	if word != "" {
+		diagnostic.Suggestions = lexerCtx.Suggester.suggest(word)
+	}
+	return diagnostic
+}
+
+var unsupportedWordRegex = regexp.MustCompile(`^[^\s?()]+`)
+
+func unsupportedWordPosition(lexerCtx *LexerContext) Position {
+	word := unsupportedWordRegex.FindString(lexerCtx.Input)
+	if word == "" {
+		_, size := utf8.DecodeRuneInString(lexerCtx.Input)
+		word = lexerCtx.Input[:size]
+	}
+
+	return lexerCtx.Source.position(lexerCtx.Input, len(word))
 }
 
 var lexerDeltas = []sm.Delta{
@@ -76,40 +122,24 @@ var lexerDeltas = []sm.Delta{
 	{Current: sm.State(stateLexerTokenise), Event: sm.Event(eventLexerUnsupportedToken), Next: sm.State(stateLexerUnsupportedOperation), Predicate: hasMathQuestion, Callback: unsupportedOperationCallback},
 }
 
-type PatternFinder interface {
-	FindString(string) string
-}
-
 type LexerContext struct {
-	Input                  string
-	FinderToConstructorMap map[PatternFinder]NewTokenFunc
+	Input     string
+	Source    *normalisedInput
+	Registry  *TokenRegistry
+	Suggester *suggester
 
 	Tokens []Token
 }
 
-func NewLexerContext(input string) *LexerContext {
+func NewLexerContext(input string, operators *OperatorRegistry) *LexerContext {
+	source := normalise(input)
+
 	return &LexerContext{
-		Input:                  input,
-		FinderToConstructorMap: newFinderToConstructorMap(),
+		Input:     source.Text,
+		Source:    source,
+		Registry:  newTokenRegistry(operators),
+		Suggester: newSuggester(operators),
 
 		Tokens: []Token{},
 	}
 }
-
-func newFinderToConstructorMap() map[PatternFinder]NewTokenFunc {
-	ftcMap := map[PatternFinder]NewTokenFunc{}
-
-	questionRegex := regexp.MustCompile(QuestionTokenPattern)
-	ftcMap[questionRegex] = NewQuestionToken
-
-	numberRegex := regexp.MustCompile(NumberTokenPattern)
-	ftcMap[numberRegex] = NewNumberToken
-
-	operandRegex := regexp.MustCompile(OperandTokenPattern)
-	ftcMap[operandRegex] = NewOperandToken
-
-	punctuationRegex := regexp.MustCompile(PunctuationTokenPattern)
-	ftcMap[punctuationRegex] = NewPunctuationToken
-
-	return ftcMap
-}
@@ -1,7 +1,11 @@
 package interp_test
 
 import (
+	"errors"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/VitoNaychev/eval-web-service/interp"
 	"github.com/VitoNaychev/eval-web-service/testutil/assert"
@@ -18,7 +22,7 @@ func TestLexer(t *testing.T) {
 			Name:  "just a question",
 			Input: "What is",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
+				&interp.QuestionToken{Value: "what is"},
 			},
 			ExpectedError: nil,
 		},
@@ -50,8 +54,8 @@ func TestLexer(t *testing.T) {
 			Name:  "question with a number",
 			Input: "What is 5",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"5"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "5"},
 			},
 			ExpectedError: nil,
 		},
@@ -59,8 +63,8 @@ func TestLexer(t *testing.T) {
 			Name:  "question with an operand",
 			Input: "What is plus",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.OperandToken{"plus"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.OperandToken{Value: "plus"},
 			},
 			ExpectedError: nil,
 		},
@@ -68,32 +72,32 @@ func TestLexer(t *testing.T) {
 			Name:  "question with punctuation",
 			Input: "What is ?",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedError: nil,
 		},
 		{
 			Name:           "error on question with unknown operand",
-			Input:          "What is cubed",
+			Input:          "What is factorial",
 			ExpectedTokens: nil,
 			ExpectedError:  interp.ErrUnsupportedOperation,
 		},
 		{
 			Name:           "error on question with number and unknown operand",
-			Input:          "What is 3 cubed",
+			Input:          "What is 3 factorial",
 			ExpectedTokens: nil,
 			ExpectedError:  interp.ErrUnsupportedOperation,
 		},
 		{
 			Name:           "error on question with known operand and unknown operand",
-			Input:          "What is 3 cubed",
+			Input:          "What is 3 factorial",
 			ExpectedTokens: nil,
 			ExpectedError:  interp.ErrUnsupportedOperation,
 		},
 		{
 			Name:           "error on question with punctuation and unknown operand",
-			Input:          "What is cubed?",
+			Input:          "What is factorial?",
 			ExpectedTokens: nil,
 			ExpectedError:  interp.ErrUnsupportedOperation,
 		},
@@ -101,9 +105,9 @@ func TestLexer(t *testing.T) {
 			Name:  "question with number and operand",
 			Input: "What is 3 plus",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"3"},
-				&interp.OperandToken{"plus"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "plus"},
 			},
 			ExpectedError: nil,
 		},
@@ -111,9 +115,9 @@ func TestLexer(t *testing.T) {
 			Name:  "question with number and punctuation",
 			Input: "What is 3?",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"3"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "3"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedError: nil,
 		},
@@ -121,9 +125,9 @@ func TestLexer(t *testing.T) {
 			Name:  "question with operand and number",
 			Input: "What is plus 3",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"3"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
 			},
 			ExpectedError: nil,
 		},
@@ -131,9 +135,9 @@ func TestLexer(t *testing.T) {
 			Name:  "question with operand and punctuation",
 			Input: "What is plus?",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.OperandToken{"plus"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedError: nil,
 		},
@@ -141,9 +145,9 @@ func TestLexer(t *testing.T) {
 			Name:  "question with punctuation and number",
 			Input: "What is ? plus",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.PunctuationToken{"?"},
-				&interp.OperandToken{"plus"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.PunctuationToken{Value: "?"},
+				&interp.OperandToken{Value: "plus"},
 			},
 			ExpectedError: nil,
 		},
@@ -151,9 +155,9 @@ func TestLexer(t *testing.T) {
 			Name:  "question with punctuation and operand",
 			Input: "What is ? 3",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.PunctuationToken{"?"},
-				&interp.NumberToken{"3"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.PunctuationToken{Value: "?"},
+				&interp.NumberToken{Value: "3"},
 			},
 			ExpectedError: nil,
 		},
@@ -161,10 +165,10 @@ func TestLexer(t *testing.T) {
 			Name:  "question with number, operand, number",
 			Input: "What is 3 plus 3",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"3"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"3"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
 			},
 			ExpectedError: nil,
 		},
@@ -172,11 +176,11 @@ func TestLexer(t *testing.T) {
 			Name:  "question with number, operand, number, punctuation",
 			Input: "What is 3 plus 3?",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"3"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"3"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedError: nil,
 		},
@@ -184,12 +188,12 @@ func TestLexer(t *testing.T) {
 			Name:  "question with number and operand, number, operand, punctuation",
 			Input: "What is 3 plus 10 minus ?",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"3"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"10"},
-				&interp.OperandToken{"minus"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "10"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedError: nil,
 		},
@@ -197,13 +201,13 @@ func TestLexer(t *testing.T) {
 			Name:  "question with number and operand, number, operand, number, punctuation",
 			Input: "What is 3 plus 10 minus 5?",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"3"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"10"},
-				&interp.OperandToken{"minus"},
-				&interp.NumberToken{"5"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "10"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.NumberToken{Value: "5"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedError: nil,
 		},
@@ -211,13 +215,111 @@ func TestLexer(t *testing.T) {
 			Name:  "question with number and operand, number, operand, number, punctuation",
 			Input: "What is 3plus10minus5?",
 			ExpectedTokens: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"3"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"10"},
-				&interp.OperandToken{"minus"},
-				&interp.NumberToken{"5"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "10"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.NumberToken{Value: "5"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:  "question with grouped subexpression",
+			Input: "What is (2 plus 3) multiplied by 4?",
+			ExpectedTokens: []interp.Token{
+				&interp.QuestionToken{Value: "what is"},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.NumberToken{Value: "2"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.CloseGroupToken{Value: ")"},
+				&interp.OperandToken{Value: "multiplied by"},
+				&interp.NumberToken{Value: "4"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:  "assignment",
+			Input: "Let x be 5.",
+			ExpectedTokens: []interp.Token{
+				&interp.AssignmentToken{Value: "let"},
+				&interp.IdentifierToken{Value: "x"},
+				&interp.BindingToken{Value: "be"},
+				&interp.NumberToken{Value: "5"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:  "question with variables",
+			Input: "What is x1 multiplied by y?",
+			ExpectedTokens: []interp.Token{
+				&interp.QuestionToken{Value: "what is"},
+				&interp.IdentifierToken{Value: "x1"},
+				&interp.OperandToken{Value: "multiplied by"},
+				&interp.IdentifierToken{Value: "y"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:  "question with the previous result",
+			Input: "What is the result multiplied by that?",
+			ExpectedTokens: []interp.Token{
+				&interp.QuestionToken{Value: "what is"},
+				&interp.PreviousResultToken{Value: "the result"},
+				&interp.OperandToken{Value: "multiplied by"},
+				&interp.PreviousResultToken{Value: "that"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "error on a word that isn't a variable",
+			Input:          "What is xy plus 1?",
+			ExpectedTokens: nil,
+			ExpectedError:  interp.ErrUnsupportedOperation,
+		},
+		{
+			Name:  "question with signed number",
+			Input: "What is -3 plus 5?",
+			ExpectedTokens: []interp.Token{
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "-3"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "5"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:  "question with decimal numbers",
+			Input: "What is -0.5 plus 2.25.",
+			ExpectedTokens: []interp.Token{
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: "-0.5"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "2.25"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:  "yes/no question",
+			Input: "Is 5 greater than 3 or 1 at least 2?",
+			ExpectedTokens: []interp.Token{
+				&interp.YesNoQuestionToken{Value: "is"},
+				&interp.NumberToken{Value: "5"},
+				&interp.ComparisonToken{Value: "greater than"},
+				&interp.NumberToken{Value: "3"},
+				&interp.ConjunctionToken{Value: "or"},
+				&interp.NumberToken{Value: "1"},
+				&interp.ComparisonToken{Value: "at least"},
+				&interp.NumberToken{Value: "2"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedError: nil,
 		},
@@ -227,8 +329,545 @@ func TestLexer(t *testing.T) {
 		t.Run(test.Name, func(t *testing.T) {
 			gotTokens, gotError := interp.Lex(test.Input)
 
-			assert.Equal(t, gotTokens, test.ExpectedTokens)
-			assert.Equal(t, gotError, test.ExpectedError)
+			assert.Equal(t, withoutPositions(gotTokens), test.ExpectedTokens)
+			assert.ErrorIs(t, gotError, test.ExpectedError)
 		})
 	}
 }
+
+func TestLexerNumberWords(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		ExpectedNumber string
+	}{
+		{"zero", "zero", "0"},
+		{"single digit", "seven", "7"},
+		{"teen", "thirteen", "13"},
+		{"multiple of ten", "forty", "40"},
+		{"hyphenated", "twenty-three", "23"},
+		{"space separated tens and ones", "ninety nine", "99"},
+		{"hundred", "one hundred", "100"},
+		{"hundred with and", "one hundred and five", "105"},
+		{"hundred without and", "three hundred forty-two", "342"},
+		{"thousand", "two thousand", "2000"},
+		{"thousand with and", "one thousand and one", "1001"},
+		{"hundreds of thousands", "seven hundred and fifty thousand three hundred", "750300"},
+		{"million", "twelve million four hundred thousand and six", "12400006"},
+		{"billion", "three billion two million", "3002000000"},
+		{"largest", "nine hundred and ninety-nine trillion nine hundred and ninety-nine billion nine hundred and ninety-nine million nine hundred and ninety-nine thousand nine hundred and ninety-nine", "999999999999999"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			input := "What is " + test.Input + "?"
+			wantTokens := []interp.Token{
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: test.ExpectedNumber},
+				&interp.PunctuationToken{Value: "?"},
+			}
+
+			gotTokens, gotError := interp.Lex(input)
+			assert.RequireNoError(t, gotError)
+
+			assert.Equal(t, withoutPositions(gotTokens), wantTokens)
+		})
+	}
+
+	t.Run("number words in an expression", func(t *testing.T) {
+		wantTokens := []interp.Token{
+			&interp.QuestionToken{Value: "what is"},
+			&interp.NumberToken{Value: "21"},
+			&interp.OperandToken{Value: "plus"},
+			&interp.NumberToken{Value: "3"},
+			&interp.OperandToken{Value: "multiplied by"},
+			&interp.NumberToken{Value: "105"},
+			&interp.PunctuationToken{Value: "?"},
+		}
+
+		gotTokens, gotError := interp.Lex("What is twenty-one plus 3 multiplied by one hundred and five?")
+		assert.RequireNoError(t, gotError)
+
+		assert.Equal(t, withoutPositions(gotTokens), wantTokens)
+	})
+
+	t.Run("stops at a word that doesn't continue the number", func(t *testing.T) {
+		wantTokens := []interp.Token{
+			&interp.QuestionToken{Value: "what is"},
+			&interp.NumberToken{Value: "5"},
+			&interp.NumberToken{Value: "5"},
+			&interp.PunctuationToken{Value: "?"},
+		}
+
+		gotTokens, gotError := interp.Lex("What is five five?")
+		assert.RequireNoError(t, gotError)
+
+		assert.Equal(t, withoutPositions(gotTokens), wantTokens)
+	})
+
+	t.Run("error on a scale without a number", func(t *testing.T) {
+		_, gotError := interp.Lex("What is hundred?")
+
+		assert.ErrorIs(t, gotError, interp.ErrUnsupportedOperation)
+	})
+
+	t.Run("error on a number word prefix", func(t *testing.T) {
+		_, gotError := interp.Lex("What is tenth?")
+
+		assert.ErrorIs(t, gotError, interp.ErrUnsupportedOperation)
+	})
+}
+
+func TestLexerLongInput(t *testing.T) {
+	german, err := interp.NewOperatorRegistry().Localise(interp.German)
+	assert.RequireNoError(t, err)
+
+	cases := []struct {
+		Name  string
+		Lex   interp.LexFunc
+		Input string
+	}{
+		{"many operations", interp.Lex, "What is 1" + strings.Repeat(" plus 1", 4000) + "?"},
+		{"many number words", interp.Lex, "What is one" + strings.Repeat(" plus one", 4000) + "?"},
+		{"ambiguous compound", german.Lex, "Was ist " + strings.Repeat("dreizehn", 24) + "x plus 1?"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			done := make(chan struct{})
+			go func() {
+				test.Lex(test.Input)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("lexing took longer than 2s")
+			}
+		})
+	}
+}
+
+func TestLexerNumerals(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          string
+		ExpectedNumber string
+	}{
+		{"hexadecimal", "0x1F", "31"},
+		{"octal", "0o17", "15"},
+		{"binary", "0b101", "5"},
+		{"negative hexadecimal", "-0xff", "-255"},
+		{"roman", "XIV", "14"},
+		{"roman with subtraction", "MCMXCIV", "1994"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			input := "What is " + test.Input + "?"
+			wantTokens := []interp.Token{
+				&interp.QuestionToken{Value: "what is"},
+				&interp.NumberToken{Value: test.ExpectedNumber},
+				&interp.PunctuationToken{Value: "?"},
+			}
+
+			gotTokens, gotError := interp.Lex(input)
+			assert.RequireNoError(t, gotError)
+
+			assert.Equal(t, withoutPositions(gotTokens), wantTokens)
+		})
+	}
+
+	t.Run("reads a single letter as an identifier", func(t *testing.T) {
+		wantTokens := []interp.Token{
+			&interp.QuestionToken{Value: "what is"},
+			&interp.IdentifierToken{Value: "x"},
+			&interp.PunctuationToken{Value: "?"},
+		}
+
+		gotTokens, gotError := interp.Lex("What is x?")
+		assert.RequireNoError(t, gotError)
+
+		assert.Equal(t, withoutPositions(gotTokens), wantTokens)
+	})
+
+	t.Run("leaves single-letter roman numerals to the identifiers", func(t *testing.T) {
+		wantTokens := []interp.Token{
+			&interp.QuestionToken{Value: "what is"},
+			&interp.NumberToken{Value: "1009"},
+			&interp.OperandToken{Value: "plus"},
+			&interp.IdentifierToken{Value: "i"},
+			&interp.PunctuationToken{Value: "?"},
+		}
+
+		gotTokens, gotError := interp.Lex("What is MIX plus I?")
+		assert.RequireNoError(t, gotError)
+
+		assert.Equal(t, withoutPositions(gotTokens), wantTokens)
+	})
+
+	t.Run("error on a non-canonical roman numeral", func(t *testing.T) {
+		_, gotError := interp.Lex("What is IIII?")
+
+		assert.ErrorIs(t, gotError, interp.ErrUnsupportedOperation)
+	})
+
+	t.Run("reads the numeral system of a conversion", func(t *testing.T) {
+		wantTokens := []interp.Token{
+			&interp.QuestionToken{Value: "what is"},
+			&interp.NumberToken{Value: "42"},
+			&interp.ConversionToken{Value: "in"},
+			&interp.NumeralSystemToken{Value: "roman numerals"},
+			&interp.PunctuationToken{Value: "?"},
+		}
+
+		gotTokens, gotError := interp.Lex("What is 42 in Roman numerals?")
+		assert.RequireNoError(t, gotError)
+
+		assert.Equal(t, withoutPositions(gotTokens), wantTokens)
+	})
+}
+
+func TestNormalise(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{"folds case", "WHAT IS Five Plus 3?", "what is five plus 3?"},
+		{"collapses whitespace", "What  is\t5\n plus   3?", "what is 5 plus 3?"},
+		{"collapses unicode whitespace", "What is 5　plus 3?", "what is 5 plus 3?"},
+		{"trims surrounding whitespace", "  What is 5?  ", "what is 5?"},
+		{"removes space before punctuation", "What is 5 ?", "what is 5?"},
+		{"question and exclamation marks", "What is 5?!", "what is 5?"},
+		{"repeated question marks", "What is 5???", "what is 5?"},
+		{"full-width question mark", "What is 5？", "what is 5?"},
+		{"full stop", "Calculate 5 plus 3.", "calculate 5 plus 3?"},
+		{"keeps a lone exclamation mark", "What is 5!", "what is 5!"},
+		{"ends every sentence", "What is 1 plus 1 ?! What is 2 plus 2.", "what is 1 plus 1? what is 2 plus 2?"},
+		{"keeps decimal points", "What is 1.5? What is 2.5.", "what is 1.5? what is 2.5?"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, interp.Normalise(test.Input), test.Expected)
+		})
+	}
+}
+
+func TestSplitInput(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected []string
+	}{
+		{"single sentence", "What is 5?", []string{"What is 5?"}},
+		{"several sentences", "What is 1 plus 1? Let x be 2.", []string{"What is 1 plus 1?", "Let x be 2."}},
+		{"unterminated last sentence", "What is 1? What is 2", []string{"What is 1?", "What is 2"}},
+		{"surrounding whitespace", "  What is 1 ?!\tWhat is 2?  ", []string{"What is 1 ?!", "What is 2?"}},
+		{"unreadable word", "What is 1 plus 1? Who are you?", []string{"What is 1 plus 1?", "Who are you?"}},
+		{"decimal point", "What is 1.5 plus 1? What is 2?", []string{"What is 1.5 plus 1?", "What is 2?"}},
+		{"no sentence mark", "What is 5", []string{"What is 5"}},
+		{"empty input", "", []string{""}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			gotSentences := []string{}
+			for _, pos := range interp.SplitInput(test.Input) {
+				gotSentences = append(gotSentences, test.Input[pos.Offset:pos.Offset+pos.Length])
+			}
+
+			assert.Equal(t, gotSentences, test.Expected)
+		})
+	}
+}
+
+func TestLexerQuestionForms(t *testing.T) {
+	cases := []struct {
+		Name             string
+		Input            string
+		ExpectedQuestion string
+	}{
+		{"what is", "what is 5 plus 3?", "what is"},
+		{"how much is", "How much is 5 plus 3?", "how much is"},
+		{"calculate", "Calculate 5 plus 3.", "calculate"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			wantTokens := []interp.Token{
+				&interp.QuestionToken{Value: test.ExpectedQuestion},
+				&interp.NumberToken{Value: "5"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.PunctuationToken{Value: "?"},
+			}
+
+			gotTokens, gotError := interp.Lex(test.Input)
+			assert.RequireNoError(t, gotError)
+
+			assert.Equal(t, withoutPositions(gotTokens), wantTokens)
+		})
+	}
+}
+
+func TestLexerSuggestions(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected []string
+	}{
+		{"transposed letters", "What is 5 plsu 3?", []string{"plus"}},
+		{"missing letter", "What is 5 mnus 3?", []string{"minus"}},
+		{"word of a phrase", "What is 5 multiplyed by 3?", []string{"multiplied"}},
+		{"number word", "What is fiev plus 3?", []string{"five"}},
+		{"question", "Waht is 5?", []string{"what"}},
+		{"comparison", "Is 5 greter than 3?", []string{"greater"}},
+		{"nothing close", "What is 5 xyzzy 3?", nil},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := interp.Lex(test.Input)
+
+			var diagnostic *interp.Diagnostic
+			assert.Equal(t, errors.As(err, &diagnostic), true)
+
+			if len(test.Expected) == 0 {
+				assert.Equal(t, len(diagnostic.Suggestions), 0)
+				return
+			}
+			assert.Equal(t, diagnostic.Suggestions, test.Expected)
+		})
+	}
+
+	t.Run("adds suggestions to the error message", func(t *testing.T) {
+		_, err := interp.Lex("What is 5 plsu 3?")
+
+		assert.Equal(t, err.Error(), `unuspported operation at "plsu" (offset 10), did you mean 'plus'?`)
+	})
+
+	t.Run("suggests words of the locale", func(t *testing.T) {
+		operators, err := interp.NewOperatorRegistry().Localise(interp.German)
+		assert.RequireNoError(t, err)
+
+		_, err = operators.Lex("Was ist 5 plis 3?")
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(err, &diagnostic), true)
+		assert.Equal(t, diagnostic.Suggestions, []string{"plus"})
+	})
+
+	t.Run("limits suggestions to the maximum distance", func(t *testing.T) {
+		operators := interp.NewOperatorRegistry()
+		operators.SetSuggestionDistance(1)
+
+		_, err := operators.Lex("What is 5 plsu 3?")
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(err, &diagnostic), true)
+		assert.Equal(t, diagnostic.Suggestions, []string{"plus"})
+
+		_, err = operators.Lex("What is 5 puls 3?")
+		assert.Equal(t, errors.As(err, &diagnostic), true)
+		assert.Equal(t, diagnostic.Suggestions, []string{"plus"})
+
+		_, err = operators.Lex("What is 5 pxlsu 3?")
+		assert.Equal(t, errors.As(err, &diagnostic), true)
+		assert.Equal(t, len(diagnostic.Suggestions), 0)
+	})
+
+	t.Run("turns suggestions off at distance zero", func(t *testing.T) {
+		operators := interp.NewOperatorRegistry()
+		operators.SetSuggestionDistance(0)
+
+		_, err := operators.Lex("What is 5 plsu 3?")
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(err, &diagnostic), true)
+		assert.Equal(t, len(diagnostic.Suggestions), 0)
+	})
+}
+
+func TestTokenRegistry(t *testing.T) {
+	t.Run("prefers the longest match", func(t *testing.T) {
+		registry := interp.NewTokenRegistry()
+		registry.Register(regexp.MustCompile(`^square`), interp.NewOperandToken, 1)
+		registry.Register(regexp.MustCompile(`^squared`), interp.NewOperandToken, 0)
+
+		constructor, gotValue, ok := registry.Match("squared?")
+		assert.Equal(t, ok, true)
+
+		assert.Equal(t, gotValue, "squared")
+		assert.Equal(t, constructor(gotValue, interp.Position{}), interp.Token(&interp.OperandToken{Value: "squared"}))
+	})
+
+	t.Run("breaks ties by priority", func(t *testing.T) {
+		registry := interp.NewTokenRegistry()
+		registry.Register(regexp.MustCompile(`^ten`), interp.NewOperandToken, 0)
+		registry.Register(regexp.MustCompile(`^ten`), interp.NewNumberWordToken, 1)
+
+		constructor, gotValue, ok := registry.Match("ten plus 1")
+		assert.Equal(t, ok, true)
+
+		assert.Equal(t, constructor(gotValue, interp.Position{}), interp.Token(&interp.NumberToken{Value: "10"}))
+	})
+
+	t.Run("breaks equal priorities by registration order", func(t *testing.T) {
+		registry := interp.NewTokenRegistry()
+		registry.Register(regexp.MustCompile(`^ten`), interp.NewOperandToken, 0)
+		registry.Register(regexp.MustCompile(`^ten`), interp.NewNumberWordToken, 0)
+
+		constructor, gotValue, ok := registry.Match("ten plus 1")
+		assert.Equal(t, ok, true)
+
+		assert.Equal(t, constructor(gotValue, interp.Position{}), interp.Token(&interp.OperandToken{Value: "ten"}))
+	})
+
+	t.Run("reports no match", func(t *testing.T) {
+		registry := interp.NewTokenRegistry()
+		registry.Register(regexp.MustCompile(`^ten`), interp.NewNumberWordToken, 0)
+
+		_, _, ok := registry.Match("factorial")
+
+		assert.Equal(t, ok, false)
+	})
+}
+
+func FuzzLexerIsDeterministic(f *testing.F) {
+	f.Add("What is 3 plus 10 minus 5?")
+	f.Add("What is (2 plus 3) multiplied by 4?")
+	f.Add("What is twenty-one raised to the power of two squared?")
+	f.Add("How much is the square root of one hundred and five?!")
+	f.Add("What is 3plus10minus5?")
+	f.Add("What is 3 factorial?")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		wantTokens, wantError := interp.Lex(input)
+
+		for i := 0; i < 20; i++ {
+			gotTokens, gotError := interp.Lex(input)
+
+			assert.Equal(t, gotTokens, wantTokens)
+			assert.Equal(t, gotError, wantError)
+		}
+	})
+}
+
+func withoutPositions(tokens []interp.Token) []interp.Token {
+	if tokens == nil {
+		return nil
+	}
+
+	stripped := []interp.Token{}
+	for _, token := range tokens {
+		value := token.GetToken().(string)
+
+		switch token.(type) {
+		case *interp.QuestionToken:
+			stripped = append(stripped, &interp.QuestionToken{Value: value})
+		case *interp.NumberToken:
+			stripped = append(stripped, &interp.NumberToken{Value: value})
+		case *interp.DateToken:
+			stripped = append(stripped, &interp.DateToken{Value: value})
+		case *interp.OperandToken:
+			stripped = append(stripped, &interp.OperandToken{Value: value})
+		case *interp.PunctuationToken:
+			stripped = append(stripped, &interp.PunctuationToken{Value: value})
+		case *interp.OpenGroupToken:
+			stripped = append(stripped, &interp.OpenGroupToken{Value: value})
+		case *interp.CloseGroupToken:
+			stripped = append(stripped, &interp.CloseGroupToken{Value: value})
+		case *interp.AssignmentToken:
+			stripped = append(stripped, &interp.AssignmentToken{Value: value})
+		case *interp.BindingToken:
+			stripped = append(stripped, &interp.BindingToken{Value: value})
+		case *interp.IdentifierToken:
+			stripped = append(stripped, &interp.IdentifierToken{Value: value})
+		case *interp.PreviousResultToken:
+			stripped = append(stripped, &interp.PreviousResultToken{Value: value})
+		case *interp.SeparatorToken:
+			stripped = append(stripped, &interp.SeparatorToken{Value: value})
+		case *interp.YesNoQuestionToken:
+			stripped = append(stripped, &interp.YesNoQuestionToken{Value: value})
+		case *interp.ComparisonToken:
+			stripped = append(stripped, &interp.ComparisonToken{Value: value})
+		case *interp.ConjunctionToken:
+			stripped = append(stripped, &interp.ConjunctionToken{Value: value})
+		case *interp.UnitToken:
+			stripped = append(stripped, &interp.UnitToken{Value: value})
+		case *interp.ConversionToken:
+			stripped = append(stripped, &interp.ConversionToken{Value: value})
+		case *interp.NumeralSystemToken:
+			stripped = append(stripped, &interp.NumeralSystemToken{Value: value})
+		}
+	}
+	return stripped
+}
+
+func TestLexerPositions(t *testing.T) {
+	t.Run("records positions in the original input", func(t *testing.T) {
+		wantPositions := []interp.Position{
+			{Offset: 0, Length: 8},
+			{Offset: 9, Length: 2},
+			{Offset: 12, Length: 4},
+			{Offset: 17, Length: 10},
+			{Offset: 27, Length: 3},
+		}
+
+		gotTokens, err := interp.Lex("What  is 21 PLUS twenty-two？")
+		assert.RequireNoError(t, err)
+
+		gotPositions := []interp.Position{}
+		for _, token := range gotTokens {
+			gotPositions = append(gotPositions, token.GetPosition())
+		}
+
+		assert.Equal(t, gotPositions, wantPositions)
+	})
+
+	t.Run("records positions across sentences", func(t *testing.T) {
+		wantPositions := []interp.Position{
+			{Offset: 0, Length: 7},
+			{Offset: 8, Length: 1},
+			{Offset: 10, Length: 2},
+			{Offset: 13, Length: 7},
+			{Offset: 21, Length: 1},
+			{Offset: 22, Length: 1},
+		}
+
+		gotTokens, err := interp.Lex("What is 1 ?! What is 2.")
+		assert.RequireNoError(t, err)
+
+		gotPositions := []interp.Position{}
+		for _, token := range gotTokens {
+			gotPositions = append(gotPositions, token.GetPosition())
+		}
+
+		assert.Equal(t, gotPositions, wantPositions)
+	})
+
+	t.Run("points unsupported operations at the offending word", func(t *testing.T) {
+		_, err := interp.Lex("What is 5  Factorial?")
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(err, &diagnostic), true)
+		assert.ErrorIs(t, err, interp.ErrUnsupportedOperation)
+
+		assert.Equal(t, diagnostic.Position, interp.Position{Offset: 11, Length: 9})
+		assert.Equal(t, diagnostic.Text, "Factorial")
+	})
+
+	t.Run("points non-math questions at the first word", func(t *testing.T) {
+		_, err := interp.Lex("Who is 5?")
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(err, &diagnostic), true)
+		assert.ErrorIs(t, err, interp.ErrNonMathQuestion)
+
+		assert.Equal(t, diagnostic.Position, interp.Position{Offset: 0, Length: 3})
+		assert.Equal(t, diagnostic.Text, "Who")
+		assert.Equal(t, diagnostic.Expected, []string{"question"})
+	})
+}
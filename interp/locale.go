@@ -0,0 +1,185 @@
+package interp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Locale supplies the words of one natural language to the lexer: the
+// phrases that open a question or a yes/no question, the phrases that open
+// and bind an assignment, the phrases that refer to the previous result, the
+// phrases of the operators, the comparisons and the conjunctions, the
+// spelled-out numbers, the names of the units and of the numeral systems and
+// the phrases that convert to one of them, the separators between the
+// arguments of a function and the punctuation marks that end a statement.
+//
+// Operators maps the phrase an operator is registered under to the phrases
+// used for it in the locale. Operators without an entry keep their phrase.
+// Units maps the Name of a unit to its phrases, which usually include its
+// plural; units without an entry can't be used in the locale.
+// The conjunctions also separate the arguments of a function, so they
+// aren't listed among the Separators.
+type Locale struct {
+	Tag             string
+	Questions       []string
+	YesNoQuestions  []string
+	Assignments     []string
+	Bindings        []string
+	PreviousResults []string
+	Operators       map[string][]string
+	Comparisons     map[Comparison][]string
+	Conjunctions    map[Conjunction][]string
+	NumberWords     NumberWords
+	Units           map[string][]string
+	NumeralSystems  map[NumeralSystem][]string
+	Conversions     []string
+	Separators      []string
+	Punctuation     []string
+}
+
+// Locales lists the locale packs that ship with the package.
+var Locales = []*Locale{English, German, Bulgarian}
+
+// LookupLocale returns the locale pack for a language tag such as "de" or
+// "de-AT". A tag with a region falls back to the pack of its language.
+func LookupLocale(tag string) (*Locale, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	language, _, _ := strings.Cut(tag, "-")
+
+	for _, locale := range Locales {
+		if locale.Tag == tag || locale.Tag == language {
+			return locale, true
+		}
+	}
+	return nil, false
+}
+
+// comparison returns the comparison a phrase of the locale stands for.
+func (l *Locale) comparison(phrase string) (Comparison, bool) {
+	for comparison, phrases := range l.Comparisons {
+		for _, p := range phrases {
+			if p == phrase {
+				return comparison, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// conjunction returns the conjunction a phrase of the locale stands for.
+func (l *Locale) conjunction(phrase string) (Conjunction, bool) {
+	for conjunction, phrases := range l.Conjunctions {
+		for _, p := range phrases {
+			if p == phrase {
+				return conjunction, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// unit returns the unit a phrase of the locale names.
+func (l *Locale) unit(phrase string) (*Unit, bool) {
+	for name, phrases := range l.Units {
+		for _, p := range phrases {
+			if p == phrase {
+				return lookupUnit(name)
+			}
+		}
+	}
+	return nil, false
+}
+
+// numeralSystem returns the numeral system a phrase of the locale names.
+func (l *Locale) numeralSystem(phrase string) (NumeralSystem, bool) {
+	for system, phrases := range l.NumeralSystems {
+		for _, p := range phrases {
+			if p == phrase {
+				return system, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (l *Locale) comparisonPhrases() phraseFinder {
+	finder := phraseFinder{}
+	for _, phrases := range l.Comparisons {
+		finder = append(finder, phrases...)
+	}
+	return finder
+}
+
+func (l *Locale) conjunctionPhrases() phraseFinder {
+	finder := phraseFinder{}
+	for _, phrases := range l.Conjunctions {
+		finder = append(finder, phrases...)
+	}
+	return finder
+}
+
+func (l *Locale) unitPhrases() phraseFinder {
+	finder := phraseFinder{}
+	for _, phrases := range l.Units {
+		finder = append(finder, phrases...)
+	}
+	return finder
+}
+
+func (l *Locale) numeralSystemPhrases() phraseFinder {
+	finder := phraseFinder{}
+	for _, phrases := range l.NumeralSystems {
+		finder = append(finder, phrases...)
+	}
+	return finder
+}
+
+// phraseFinder finds the longest of a list of phrases at the start of the
+// input.
+type phraseFinder []string
+
+func (p phraseFinder) FindString(input string) string {
+	longest := ""
+	for _, phrase := range p {
+		if len(phrase) > len(longest) && hasPhrasePrefix(input, phrase) {
+			longest = phrase
+		}
+	}
+	return longest
+}
+
+// identifierFinder finds a variable name at the start of the input: a single
+// letter, optionally followed by digits, that doesn't run into another
+// letter.
+type identifierFinder struct{}
+
+func (i identifierFinder) FindString(input string) string {
+	first, size := utf8.DecodeRuneInString(input)
+	if !unicode.IsLetter(first) {
+		return ""
+	}
+
+	end := size
+	for end < len(input) && input[end] >= '0' && input[end] <= '9' {
+		end++
+	}
+
+	next, _ := utf8.DecodeRuneInString(input[end:])
+	if unicode.IsLetter(next) {
+		return ""
+	}
+	return input[:end]
+}
+
+// hasPhrasePrefix reports whether the input starts with the phrase as a
+// whole word, so "hoch drei" isn't found in "hoch dreißig".
+func hasPhrasePrefix(input, phrase string) bool {
+	if phrase == "" || !strings.HasPrefix(input, phrase) {
+		return false
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(phrase)
+	next, _ := utf8.DecodeRuneInString(input[len(phrase):])
+	return !unicode.IsLetter(last) || !unicode.IsLetter(next)
+}
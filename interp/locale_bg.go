@@ -0,0 +1,119 @@
+package interp
+
+var Bulgarian = &Locale{
+	Tag:             "bg",
+	Questions:       []string{"колко е", "колко прави", "пресметни", "изчисли"},
+	YesNoQuestions:  []string{"дали"},
+	Assignments:     []string{"нека"},
+	Bindings:        []string{"бъде", "е равно на"},
+	PreviousResults: []string{"резултатът", "резултата", "това"},
+	Operators: map[string][]string{
+		"plus":                   {"плюс"},
+		"minus":                  {"минус"},
+		"increased by":           {"увеличено с"},
+		"decreased by":           {"намалено с"},
+		"multiplied by":          {"по", "умножено по"},
+		"divided by":             {"делено на"},
+		"modulo":                 {"по модул"},
+		"percent of":             {"процента от", "процент от"},
+		"raised to the power of": {"на степен"},
+		"the square root of":     {"корен от", "квадратен корен от"},
+		"squared":                {"на квадрат"},
+		"cubed":                  {"на куб"},
+		"percent":                {"процента", "процент"},
+		"the sum of":             {"сумата на"},
+		"the product of":         {"произведението на"},
+		"the average of":         {"средното на", "средната стойност на"},
+		"the minimum of":         {"минимума на", "минимумът на"},
+		"the maximum of":         {"максимума на", "максимумът на"},
+		"the median of":          {"медианата на"},
+	},
+	Comparisons: map[Comparison][]string{
+		GreaterThan: {"е по-голямо от", "е повече от"},
+		LessThan:    {"е по-малко от"},
+		EqualTo:     {"е колкото"},
+		AtLeast:     {"е поне"},
+		AtMost:      {"е най-много"},
+	},
+	Conjunctions: map[Conjunction][]string{
+		And: {"и"},
+		Or:  {"или"},
+	},
+	NumberWords: NumberWords{
+		Zero: "нула",
+		Ones: map[string]int64{
+			"едно": 1, "един": 1, "една": 1, "две": 2, "два": 2, "три": 3, "четири": 4,
+			"пет": 5, "шест": 6, "седем": 7, "осем": 8, "девет": 9,
+		},
+		Teens: map[string]int64{
+			"десет": 10, "единадесет": 11, "дванадесет": 12, "тринадесет": 13,
+			"четиринадесет": 14, "петнадесет": 15, "шестнадесет": 16,
+			"седемнадесет": 17, "осемнадесет": 18, "деветнадесет": 19,
+		},
+		Tens: map[string]int64{
+			"двадесет": 20, "тридесет": 30, "четиридесет": 40, "петдесет": 50,
+			"шестдесет": 60, "седемдесет": 70, "осемдесет": 80, "деветдесет": 90,
+		},
+		Hundreds: map[string]int64{
+			"сто": 100, "двеста": 200, "триста": 300, "четиристотин": 400,
+			"петстотин": 500, "шестстотин": 600, "седемстотин": 700,
+			"осемстотин": 800, "деветстотин": 900,
+		},
+		Scales: map[string]int64{
+			"хиляда":   1_000,
+			"хиляди":   1_000,
+			"милион":   1_000_000,
+			"милиона":  1_000_000,
+			"милиард":  1_000_000_000,
+			"милиарда": 1_000_000_000,
+		},
+		Implicit: map[string]bool{
+			"хиляда":  true,
+			"милион":  true,
+			"милиард": true,
+		},
+		Conjunction: "и",
+		TensOrder:   TensConjunctionOnes,
+	},
+	Units: map[string][]string{
+		"millimeter":  {"милиметър", "милиметра", "милиметри"},
+		"centimeter":  {"сантиметър", "сантиметра", "сантиметри"},
+		"meter":       {"метър", "метра", "метри"},
+		"kilometer":   {"километър", "километра", "километри"},
+		"inch":        {"инч", "инча", "инчове"},
+		"foot":        {"фут", "фута"},
+		"yard":        {"ярд", "ярда"},
+		"mile":        {"миля", "мили"},
+		"milligram":   {"милиграм", "милиграма"},
+		"gram":        {"грам", "грама"},
+		"kilogram":    {"килограм", "килограма"},
+		"tonne":       {"тон", "тона"},
+		"ounce":       {"унция", "унции"},
+		"pound":       {"паунд", "паунда"},
+		"millisecond": {"милисекунда", "милисекунди"},
+		"second":      {"секунда", "секунди"},
+		"minute":      {"минута", "минути"},
+		"hour":        {"час", "часа"},
+		"day":         {"ден", "дни", "дена"},
+		"week":        {"седмица", "седмици"},
+		"bit":         {"бит", "бита"},
+		"byte":        {"байт", "байта"},
+		"kilobyte":    {"килобайт", "килобайта"},
+		"megabyte":    {"мегабайт", "мегабайта"},
+		"gigabyte":    {"гигабайт", "гигабайта"},
+		"terabyte":    {"терабайт", "терабайта"},
+		"kibibyte":    {"кибибайт", "кибибайта"},
+		"mebibyte":    {"мебибайт", "мебибайта"},
+		"gibibyte":    {"гибибайт", "гибибайта"},
+	},
+	NumeralSystems: map[NumeralSystem][]string{
+		Decimal:     {"десетична бройна система"},
+		Hexadecimal: {"шестнадесетична бройна система"},
+		Octal:       {"осмична бройна система"},
+		Binary:      {"двоична бройна система"},
+		Roman:       {"римски цифри"},
+	},
+	Conversions: []string{"в"},
+	Separators:  []string{","},
+	Punctuation: []string{"?"},
+}
@@ -0,0 +1,113 @@
+package interp
+
+var German = &Locale{
+	Tag:             "de",
+	Questions:       []string{"was ist", "wie viel ist", "wieviel ist", "berechne"},
+	YesNoQuestions:  []string{"ist"},
+	Assignments:     []string{"sei"},
+	Bindings:        []string{"gleich"},
+	PreviousResults: []string{"das ergebnis", "das"},
+	Operators: map[string][]string{
+		"plus":                   {"plus"},
+		"minus":                  {"minus"},
+		"increased by":           {"erhöht um"},
+		"decreased by":           {"verringert um"},
+		"multiplied by":          {"mal", "multipliziert mit"},
+		"divided by":             {"geteilt durch", "dividiert durch"},
+		"modulo":                 {"modulo"},
+		"percent of":             {"prozent von"},
+		"raised to the power of": {"hoch"},
+		"the square root of":     {"die wurzel aus", "die quadratwurzel aus"},
+		"squared":                {"zum quadrat", "hoch zwei"},
+		"cubed":                  {"hoch drei"},
+		"percent":                {"prozent"},
+		"the sum of":             {"die summe von"},
+		"the product of":         {"das produkt von"},
+		"the average of":         {"der durchschnitt von"},
+		"the minimum of":         {"das minimum von"},
+		"the maximum of":         {"das maximum von"},
+		"the median of":          {"der median von"},
+	},
+	Comparisons: map[Comparison][]string{
+		GreaterThan: {"größer als", "mehr als"},
+		LessThan:    {"kleiner als", "weniger als"},
+		EqualTo:     {"gleich groß wie", "genauso groß wie"},
+		AtLeast:     {"mindestens"},
+		AtMost:      {"höchstens"},
+	},
+	Conjunctions: map[Conjunction][]string{
+		And: {"und"},
+		Or:  {"oder"},
+	},
+	NumberWords: NumberWords{
+		Zero: "null",
+		Ones: map[string]int64{
+			"eins": 1, "ein": 1, "eine": 1, "zwei": 2, "drei": 3, "vier": 4, "fünf": 5,
+			"sechs": 6, "sieben": 7, "acht": 8, "neun": 9,
+		},
+		Teens: map[string]int64{
+			"zehn": 10, "elf": 11, "zwölf": 12, "dreizehn": 13, "vierzehn": 14,
+			"fünfzehn": 15, "sechzehn": 16, "siebzehn": 17, "achtzehn": 18, "neunzehn": 19,
+		},
+		Tens: map[string]int64{
+			"zwanzig": 20, "dreißig": 30, "vierzig": 40, "fünfzig": 50,
+			"sechzig": 60, "siebzig": 70, "achtzig": 80, "neunzig": 90,
+		},
+		Hundred: "hundert",
+		Scales: map[string]int64{
+			"tausend":    1_000,
+			"million":    1_000_000,
+			"millionen":  1_000_000,
+			"milliarde":  1_000_000_000,
+			"milliarden": 1_000_000_000,
+		},
+		Implicit: map[string]bool{
+			"hundert": true,
+			"tausend": true,
+		},
+		Conjunction: "und",
+		TensOrder:   OnesConjunctionTens,
+		Compound:    true,
+	},
+	Units: map[string][]string{
+		"millimeter":  {"millimeter"},
+		"centimeter":  {"zentimeter"},
+		"meter":       {"meter"},
+		"kilometer":   {"kilometer"},
+		"inch":        {"zoll"},
+		"foot":        {"fuß"},
+		"yard":        {"yard", "yards"},
+		"mile":        {"meile", "meilen"},
+		"milligram":   {"milligramm"},
+		"gram":        {"gramm"},
+		"kilogram":    {"kilogramm"},
+		"tonne":       {"tonne", "tonnen"},
+		"ounce":       {"unze", "unzen"},
+		"pound":       {"pfund"},
+		"millisecond": {"millisekunde", "millisekunden"},
+		"second":      {"sekunde", "sekunden"},
+		"minute":      {"minute", "minuten"},
+		"hour":        {"stunde", "stunden"},
+		"day":         {"tag", "tage", "tagen"},
+		"week":        {"woche", "wochen"},
+		"bit":         {"bit", "bits"},
+		"byte":        {"byte", "bytes"},
+		"kilobyte":    {"kilobyte"},
+		"megabyte":    {"megabyte"},
+		"gigabyte":    {"gigabyte"},
+		"terabyte":    {"terabyte"},
+		"kibibyte":    {"kibibyte"},
+		"mebibyte":    {"mebibyte"},
+		"gibibyte":    {"gibibyte"},
+	},
+	NumeralSystems: map[NumeralSystem][]string{
+		Decimal:     {"dezimal", "dezimalzahlen"},
+		Hexadecimal: {"hexadezimal", "hex"},
+		Octal:       {"oktal"},
+		Binary:      {"binär"},
+		Roman:       {"römischen zahlen", "römischen ziffern"},
+	},
+	Conversions: []string{"in"},
+	Separators:  []string{","},
+	Punctuation: []string{"?"},
+}
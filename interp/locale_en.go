@@ -0,0 +1,88 @@
+package interp
+
+// English is the default locale. The built-in operators are registered under
+// their English phrases, so it needs no operator translations.
+var English = &Locale{
+	Tag:             "en",
+	Questions:       []string{"what is", "how much is", "calculate"},
+	YesNoQuestions:  []string{"is"},
+	Assignments:     []string{"let"},
+	Bindings:        []string{"be"},
+	PreviousResults: []string{"the result", "that"},
+	Comparisons: map[Comparison][]string{
+		GreaterThan: {"greater than", "more than"},
+		LessThan:    {"less than"},
+		EqualTo:     {"equal to"},
+		AtLeast:     {"at least"},
+		AtMost:      {"at most"},
+	},
+	Conjunctions: map[Conjunction][]string{
+		And: {"and"},
+		Or:  {"or"},
+	},
+	NumberWords: NumberWords{
+		Zero: "zero",
+		Ones: map[string]int64{
+			"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+			"six": 6, "seven": 7, "eight": 8, "nine": 9,
+		},
+		Teens: map[string]int64{
+			"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14,
+			"fifteen": 15, "sixteen": 16, "seventeen": 17, "eighteen": 18, "nineteen": 19,
+		},
+		Tens: map[string]int64{
+			"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+			"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+		},
+		Hundred: "hundred",
+		Scales: map[string]int64{
+			"thousand": 1_000,
+			"million":  1_000_000,
+			"billion":  1_000_000_000,
+			"trillion": 1_000_000_000_000,
+		},
+		Conjunction: "and",
+		TensOrder:   TensThenOnes,
+	},
+	Units: map[string][]string{
+		"millimeter":  {"millimeter", "millimeters", "millimetre", "millimetres"},
+		"centimeter":  {"centimeter", "centimeters", "centimetre", "centimetres"},
+		"meter":       {"meter", "meters", "metre", "metres"},
+		"kilometer":   {"kilometer", "kilometers", "kilometre", "kilometres"},
+		"inch":        {"inch", "inches"},
+		"foot":        {"foot", "feet"},
+		"yard":        {"yard", "yards"},
+		"mile":        {"mile", "miles"},
+		"milligram":   {"milligram", "milligrams"},
+		"gram":        {"gram", "grams"},
+		"kilogram":    {"kilogram", "kilograms"},
+		"tonne":       {"tonne", "tonnes"},
+		"ounce":       {"ounce", "ounces"},
+		"pound":       {"pound", "pounds"},
+		"millisecond": {"millisecond", "milliseconds"},
+		"second":      {"second", "seconds"},
+		"minute":      {"minute", "minutes"},
+		"hour":        {"hour", "hours"},
+		"day":         {"day", "days"},
+		"week":        {"week", "weeks"},
+		"bit":         {"bit", "bits"},
+		"byte":        {"byte", "bytes"},
+		"kilobyte":    {"kilobyte", "kilobytes"},
+		"megabyte":    {"megabyte", "megabytes"},
+		"gigabyte":    {"gigabyte", "gigabytes"},
+		"terabyte":    {"terabyte", "terabytes"},
+		"kibibyte":    {"kibibyte", "kibibytes"},
+		"mebibyte":    {"mebibyte", "mebibytes"},
+		"gibibyte":    {"gibibyte", "gibibytes"},
+	},
+	NumeralSystems: map[NumeralSystem][]string{
+		Decimal:     {"decimal"},
+		Hexadecimal: {"hexadecimal", "hex"},
+		Octal:       {"octal"},
+		Binary:      {"binary"},
+		Roman:       {"roman numerals"},
+	},
+	Conversions: []string{"in"},
+	Separators:  []string{",", ", and"},
+	Punctuation: []string{"?"},
+}
@@ -0,0 +1,139 @@
+package interp_test
+
+import (
+	"testing"
+
+	"github.com/VitoNaychev/eval-web-service/interp"
+	"github.com/VitoNaychev/eval-web-service/testutil/assert"
+)
+
+func TestLocales(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Locale         *interp.Locale
+		Input          string
+		ExpectedResult string
+	}{
+		{"english", interp.English, "What is twenty-one plus 3?", "24"},
+		{"german operators", interp.German, "Was ist 7 geteilt durch 2 mal 4?", "12"},
+		{"german compound number", interp.German, "Was ist dreihundertfünfundvierzig plus eins?", "346"},
+		{"german scale", interp.German, "Wie viel ist zweitausenddreihundert minus hundert?", "2200"},
+		{"german separate scale", interp.German, "Berechne zwei Millionen dreihundert!?", "2000300"},
+		{"german postfix", interp.German, "Was ist 2 hoch drei?", "8"},
+		{"german phrase as a whole word", interp.German, "Was ist 2 hoch dreizehn?", "8192"},
+		{"german prefix", interp.German, "Was ist die Wurzel aus 16 geteilt durch zwei?", "2"},
+		{"german assignment", interp.German, "Sei x gleich 5 mal 2.", "10"},
+		{"bulgarian assignment", interp.Bulgarian, "Нека x е равно на 3 плюс 4.", "7"},
+		{"bulgarian operators", interp.Bulgarian, "Колко е 7 по модул 3 по 5?", "5"},
+		{"bulgarian tens and ones", interp.Bulgarian, "Колко е двадесет и три плюс 1?", "24"},
+		{"bulgarian hundreds", interp.Bulgarian, "Колко е сто двадесет и три по 2?", "246"},
+		{"bulgarian scale", interp.Bulgarian, "Пресметни две хиляди и пет делено на 5?", "401"},
+		{"bulgarian implicit scale", interp.Bulgarian, "Колко е хиляда на квадрат?", "1000000"},
+		{"german function", interp.German, "Was ist die Summe von 1, 2 und 3?", "6"},
+		{"bulgarian function", interp.Bulgarian, "Колко е максимума на 4, 9 и 2?", "9"},
+		{"german percentages", interp.German, "Was ist 15 Prozent von 200 erhöht um 10 Prozent?", "33"},
+		{"bulgarian percentages", interp.Bulgarian, "Колко е 200 намалено с 5 процента?", "190"},
+		{"german units", interp.German, "Was ist 2 Stunden plus 15 Minuten in Minuten?", "135 min"},
+		{"bulgarian units", interp.Bulgarian, "Колко е 3 километра минус 200 метра в метри?", "2800 m"},
+		{"german numeral systems", interp.German, "Was ist XIV mal III in römischen Zahlen?", "XLII"},
+		{"bulgarian numeral systems", interp.Bulgarian, "Колко е 0x1F плюс 1 в двоична бройна система?", "0b100000"},
+		{"english yes/no question", interp.English, "Is seven at least five or 2 equal to 3?", "true"},
+		{"german yes/no question", interp.German, "Ist 7 größer als 5 und 2 gleich groß wie 3?", "false"},
+		{"german binding and equality", interp.German, "Ist 2 mal 3 genauso groß wie sechs?", "true"},
+		{"bulgarian yes/no question", interp.Bulgarian, "Дали 7 е по-малко от 5 или 2 е колкото 2?", "true"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			operators, err := interp.NewOperatorRegistry().Localise(test.Locale)
+			assert.RequireNoError(t, err)
+
+			tokens, err := operators.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			tree, err := operators.Parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := operators.Interpret(tree, interp.Options{})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	t.Run("reads the previous result", func(t *testing.T) {
+		operators, err := interp.NewOperatorRegistry().Localise(interp.German)
+		assert.RequireNoError(t, err)
+
+		tokens, err := operators.Lex("Was ist das Ergebnis plus 1?")
+		assert.RequireNoError(t, err)
+
+		tree, err := operators.Parse(tokens)
+		assert.RequireNoError(t, err)
+
+		result, err := operators.Interpret(tree, interp.Options{Previous: "41"})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, result.String(), "42")
+	})
+
+	t.Run("doesn't read other locales", func(t *testing.T) {
+		operators, err := interp.NewOperatorRegistry().Localise(interp.German)
+		assert.RequireNoError(t, err)
+
+		_, gotError := operators.Lex("Was ist 5 plus five?")
+
+		assert.ErrorIs(t, gotError, interp.ErrUnsupportedOperation)
+	})
+
+	t.Run("cuts a number back to a whole word", func(t *testing.T) {
+		operators, err := interp.NewOperatorRegistry().Localise(interp.German)
+		assert.RequireNoError(t, err)
+
+		_, gotError := operators.Lex("Was ist dreiundzwanzigste?")
+
+		assert.ErrorIs(t, gotError, interp.ErrUnsupportedOperation)
+	})
+
+	t.Run("keeps operators the locale doesn't translate", func(t *testing.T) {
+		operators := interp.NewOperatorRegistry()
+		err := operators.Register(interp.Operator{
+			Phrase:     "averaged with",
+			Notation:   interp.Infix,
+			Precedence: 2,
+			Apply: func(operands []interp.Number, arithmetic interp.Arithmetic) (interp.Number, error) {
+				return operands[0], nil
+			},
+		})
+		assert.RequireNoError(t, err)
+
+		localised, err := operators.Localise(interp.Bulgarian)
+		assert.RequireNoError(t, err)
+
+		_, err = localised.Lex("Колко е 2 averaged with 4?")
+		assert.RequireNoError(t, err)
+	})
+}
+
+func TestLookupLocale(t *testing.T) {
+	cases := []struct {
+		Tag            string
+		ExpectedLocale *interp.Locale
+		ExpectedFound  bool
+	}{
+		{"en", interp.English, true},
+		{"DE", interp.German, true},
+		{"de-AT", interp.German, true},
+		{"bg_BG", interp.Bulgarian, true},
+		{"it", nil, false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Tag, func(t *testing.T) {
+			gotLocale, gotFound := interp.LookupLocale(test.Tag)
+
+			assert.Equal(t, gotFound, test.ExpectedFound)
+			assert.Equal(t, gotLocale, test.ExpectedLocale)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package interp
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var terminatorRegex = regexp.MustCompile(`\s*([!！]*[?？][?？!！]*|\.)`)
+
+// Normalise brings an input to the canonical form expected by the lexer. It
+// folds the case of the input, collapses runs of Unicode whitespace into a
+// single space, and replaces the punctuation mark variant that ends each of
+// its sentences, e.g. "?!" or the full-width "？", with a plain question mark.
+// A mark ends a sentence when it is followed by whitespace or by the end of
+// the input.
+func Normalise(input string) string {
+	return normalise(input).Text
+}
+
+// SplitInput locates the sentences of an input, each of which ends with the
+// mark that ends it, e.g. "What is 1 plus 1?" and "What is 2 plus 2?" in
+// "What is 1 plus 1? What is 2 plus 2?". The marks are found the way
+// Normalise finds them, so the input is split before it is lexed and a word
+// the lexer can't read only fails its own sentence. Text after the last mark
+// forms a sentence of its own, and an input without any marks is a single
+// sentence. The whitespace around each sentence is left out of its Position.
+func SplitInput(input string) []Position {
+	sentences := []Position{}
+
+	start := 0
+	for _, end := range normalise(input).terminators {
+		sentences = append(sentences, trimSpace(input, start, end))
+		start = end
+	}
+	if len(sentences) == 0 || strings.TrimSpace(input[start:]) != "" {
+		sentences = append(sentences, trimSpace(input, start, len(input)))
+	}
+
+	return sentences
+}
+
+func trimSpace(input string, start, end int) Position {
+	text := input[start:end]
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	offset := start + len(text) - len(trimmed)
+	return Position{
+		Offset: offset,
+		Length: len(strings.TrimRightFunc(trimmed, unicode.IsSpace)),
+	}
+}
+
+// normalisedInput keeps track of where each byte of the normalised text came
+// from, so that positions found by the lexer can be reported against the
+// original input.
+type normalisedInput struct {
+	Text string
+
+	original    string
+	starts      []int
+	ends        []int
+	terminators []int
+}
+
+func normalise(input string) *normalisedInput {
+	n := &normalisedInput{
+		original: input,
+		starts:   []int{},
+		ends:     []int{},
+	}
+
+	var builder strings.Builder
+	spaceStart, spaceEnd := -1, -1
+
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+
+		if unicode.IsSpace(r) {
+			if builder.Len() > 0 && spaceStart < 0 {
+				spaceStart = i
+			}
+			spaceEnd = i + size
+			i += size
+			continue
+		}
+
+		if spaceStart >= 0 {
+			n.write(&builder, " ", spaceStart, spaceEnd)
+			spaceStart = -1
+		}
+
+		n.write(&builder, string(unicode.ToLower(r)), i, i+size)
+		i += size
+	}
+
+	n.Text = n.replaceTerminators(builder.String())
+
+	return n
+}
+
+// replaceTerminators replaces the marks that end the sentences of the text
+// with a question mark, dropping any whitespace before them. The question
+// mark keeps the position of the mark it replaces in the original input,
+// and the end of each mark is kept in terminators.
+func (n *normalisedInput) replaceTerminators(text string) string {
+	var builder strings.Builder
+	starts, ends := []int{}, []int{}
+
+	last := 0
+	for _, match := range terminatorRegex.FindAllStringSubmatchIndex(text, -1) {
+		if match[1] < len(text) && text[match[1]] != ' ' {
+			continue
+		}
+
+		builder.WriteString(text[last:match[0]] + "?")
+		n.terminators = append(n.terminators, n.ends[match[3]-1])
+		starts = append(append(starts, n.starts[last:match[0]]...), n.starts[match[2]])
+		ends = append(append(ends, n.ends[last:match[0]]...), n.ends[match[3]-1])
+		last = match[1]
+	}
+	builder.WriteString(text[last:])
+
+	n.starts = append(starts, n.starts[last:]...)
+	n.ends = append(ends, n.ends[last:]...)
+	return builder.String()
+}
+
+func (n *normalisedInput) write(builder *strings.Builder, str string, start, end int) {
+	builder.WriteString(str)
+	for range []byte(str) {
+		n.starts = append(n.starts, start)
+		n.ends = append(n.ends, end)
+	}
+}
+
+// position maps length bytes at the start of remaining, a suffix of the
+// normalised text, to their Position in the original input.
+func (n *normalisedInput) position(remaining string, length int) Position {
+	offset := len(n.Text) - len(remaining)
+	if offset >= len(n.starts) {
+		return Position{Offset: len(n.original)}
+	}
+	if length == 0 {
+		return Position{Offset: n.starts[offset]}
+	}
+
+	start, end := n.starts[offset], n.ends[offset+length-1]
+	return Position{
+		Offset: start,
+		Length: end - start,
+	}
+}
+
+func (n *normalisedInput) text(pos Position) string {
+	return n.original[pos.Offset : pos.Offset+pos.Length]
+}
@@ -0,0 +1,700 @@
+package interp
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Arithmetic selects the number representation used while evaluating an
+// expression tree.
+type Arithmetic int
+
+const (
+	ArithmeticInteger Arithmetic = iota
+	ArithmeticFloat
+	ArithmeticDecimal
+	ArithmeticRational
+	ArithmeticBigInteger
+)
+
+// DecimalPlaces is the number of fractional digits kept when a decimal
+// result does not terminate, e.g. "1 divided by 3".
+var DecimalPlaces = 34
+
+// MaxBits bounds the size in bits of the integers, numerators and
+// denominators of the arbitrary-precision arithmetics, whose results would
+// otherwise grow without limit, e.g. when powers are nested. Results that
+// could be larger are reported as ErrOverflow before they are computed.
+var MaxBits = 1 << 17
+
+// sqrtPrecision is the mantissa size in bits used to approximate square
+// roots in decimal arithmetic, comfortably above DecimalPlaces digits.
+const sqrtPrecision = 256
+
+// Value is the result of a statement: a Number, or a Boolean answering a
+// yes/no question.
+type Value interface {
+	String() string
+}
+
+// Number is a value produced by the interpreter. Operations are only defined
+// between numbers created with the same Arithmetic.
+type Number interface {
+	Add(Number) (Number, error)
+	Sub(Number) (Number, error)
+	Mul(Number) (Number, error)
+	Div(Number) (Number, error)
+	Pow(Number) (Number, error)
+	Mod(Number) (Number, error)
+	Neg() (Number, error)
+	Sqrt() (Number, error)
+	// Cmp returns -1, 0 or +1 depending on whether the number is less than,
+	// equal to or greater than the other one.
+	Cmp(Number) int
+	String() string
+}
+
+// ParseNumber reads a number literal in the given arithmetic. The integer
+// arithmetics accept a decimal literal only when it is a whole number, e.g.
+// "2.0", and report any other one as ErrDomain.
+func ParseNumber(literal string, arithmetic Arithmetic) (Number, error) {
+	isInteger := arithmetic == ArithmeticInteger || arithmetic == ArithmeticBigInteger
+	if isInteger && strings.Contains(literal, ".") {
+		num, ok := new(big.Rat).SetString(literal)
+		if !ok {
+			return nil, NewInterpreterError("invalid number " + literal)
+		}
+		return exactNumber(num, arithmetic)
+	}
+
+	switch arithmetic {
+	case ArithmeticFloat:
+		num, err := strconv.ParseFloat(literal, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return nil, ErrOverflow
+		} else if err != nil {
+			return nil, err
+		}
+		return floatNumber(num), nil
+	case ArithmeticBigInteger:
+		num, ok := new(big.Int).SetString(literal, 10)
+		if !ok {
+			return nil, NewInterpreterError("invalid number " + literal)
+		}
+		return &bigIntNumber{num}, nil
+	case ArithmeticDecimal, ArithmeticRational:
+		num, ok := new(big.Rat).SetString(literal)
+		if !ok {
+			return nil, NewInterpreterError("invalid number " + literal)
+		}
+		if arithmetic == ArithmeticDecimal {
+			return &decimalNumber{num}, nil
+		}
+		return &rationalNumber{num}, nil
+	default:
+		num, err := strconv.Atoi(literal)
+		if errors.Is(err, strconv.ErrRange) {
+			return nil, ErrOverflow
+		} else if err != nil {
+			return nil, err
+		}
+		return intNumber(num), nil
+	}
+}
+
+type intNumber int
+
+func (i intNumber) Add(other Number) (Number, error) {
+	right := other.(intNumber)
+	if (right > 0 && i > math.MaxInt-right) || (right < 0 && i < math.MinInt-right) {
+		return nil, ErrOverflow
+	}
+	return i + right, nil
+}
+
+func (i intNumber) Sub(other Number) (Number, error) {
+	right := other.(intNumber)
+	if (right < 0 && i > math.MaxInt+right) || (right > 0 && i < math.MinInt+right) {
+		return nil, ErrOverflow
+	}
+	return i - right, nil
+}
+
+func (i intNumber) Mul(other Number) (Number, error) {
+	right := other.(intNumber)
+	if i == 0 || right == 0 {
+		return intNumber(0), nil
+	}
+
+	result := i * right
+	if result/right != i || (i == -1 && right == math.MinInt) || (right == -1 && i == math.MinInt) {
+		return nil, ErrOverflow
+	}
+	return result, nil
+}
+
+func (i intNumber) Div(other Number) (Number, error) {
+	right := other.(intNumber)
+	if right == 0 {
+		return nil, ErrDivisionByZero
+	}
+	if i == math.MinInt && right == -1 {
+		return nil, ErrOverflow
+	}
+	return i / right, nil
+}
+
+// Pow truncates results with a negative exponent towards zero, the same way
+// integer division does.
+func (i intNumber) Pow(other Number) (Number, error) {
+	exponent := other.(intNumber)
+	if exponent < 0 {
+		switch i {
+		case 0:
+			return nil, ErrDivisionByZero
+		case 1:
+			return intNumber(1), nil
+		case -1:
+			if exponent%2 == 0 {
+				return intNumber(1), nil
+			}
+			return intNumber(-1), nil
+		default:
+			return intNumber(0), nil
+		}
+	}
+
+	var result Number = intNumber(1)
+	var base Number = i
+	for exponent > 0 {
+		var err error
+		if exponent&1 == 1 {
+			if result, err = result.Mul(base); err != nil {
+				return nil, err
+			}
+		}
+
+		exponent >>= 1
+		if exponent > 0 {
+			if base, err = base.Mul(base); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (i intNumber) Mod(other Number) (Number, error) {
+	right := other.(intNumber)
+	if right == 0 {
+		return nil, ErrDivisionByZero
+	}
+	if right == -1 {
+		return intNumber(0), nil
+	}
+	return i % right, nil
+}
+
+func (i intNumber) Neg() (Number, error) {
+	if i == math.MinInt {
+		return nil, ErrOverflow
+	}
+	return -i, nil
+}
+
+// Sqrt returns the integer square root, i.e. the result rounded down.
+func (i intNumber) Sqrt() (Number, error) {
+	if i < 0 {
+		return nil, ErrDomain
+	}
+	root := new(big.Int).Sqrt(big.NewInt(int64(i)))
+	return intNumber(root.Int64()), nil
+}
+
+func (i intNumber) Cmp(other Number) int {
+	right := other.(intNumber)
+	switch {
+	case i < right:
+		return -1
+	case i > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (i intNumber) String() string {
+	return strconv.Itoa(int(i))
+}
+
+type floatNumber float64
+
+func (f floatNumber) Add(other Number) (Number, error) {
+	return checkFloat(f + other.(floatNumber))
+}
+
+func (f floatNumber) Sub(other Number) (Number, error) {
+	return checkFloat(f - other.(floatNumber))
+}
+
+func (f floatNumber) Mul(other Number) (Number, error) {
+	return checkFloat(f * other.(floatNumber))
+}
+
+func (f floatNumber) Div(other Number) (Number, error) {
+	if other.(floatNumber) == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return checkFloat(f / other.(floatNumber))
+}
+
+func (f floatNumber) Pow(other Number) (Number, error) {
+	exponent := other.(floatNumber)
+	if f == 0 && exponent < 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	result := math.Pow(float64(f), float64(exponent))
+	if math.IsNaN(result) {
+		return nil, ErrDomain
+	}
+	return checkFloat(floatNumber(result))
+}
+
+func (f floatNumber) Mod(other Number) (Number, error) {
+	if other.(floatNumber) == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return floatNumber(math.Mod(float64(f), float64(other.(floatNumber)))), nil
+}
+
+func (f floatNumber) Neg() (Number, error) {
+	return -f, nil
+}
+
+func (f floatNumber) Sqrt() (Number, error) {
+	if f < 0 {
+		return nil, ErrDomain
+	}
+	return floatNumber(math.Sqrt(float64(f))), nil
+}
+
+func checkFloat(f floatNumber) (Number, error) {
+	if math.IsInf(float64(f), 0) {
+		return nil, ErrOverflow
+	}
+	return f, nil
+}
+
+func (f floatNumber) Cmp(other Number) int {
+	right := other.(floatNumber)
+	switch {
+	case f < right:
+		return -1
+	case f > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (f floatNumber) String() string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 64)
+}
+
+// decimalNumber keeps an exact value and only rounds to DecimalPlaces when
+// it is rendered, so intermediate results never lose precision.
+type decimalNumber struct {
+	value *big.Rat
+}
+
+func (d *decimalNumber) Add(other Number) (Number, error) {
+	right := other.(*decimalNumber).value
+	if err := checkRatOperation(d.value, right); err != nil {
+		return nil, err
+	}
+	return &decimalNumber{new(big.Rat).Add(d.value, right)}, nil
+}
+
+func (d *decimalNumber) Sub(other Number) (Number, error) {
+	right := other.(*decimalNumber).value
+	if err := checkRatOperation(d.value, right); err != nil {
+		return nil, err
+	}
+	return &decimalNumber{new(big.Rat).Sub(d.value, right)}, nil
+}
+
+func (d *decimalNumber) Mul(other Number) (Number, error) {
+	right := other.(*decimalNumber).value
+	if err := checkRatOperation(d.value, right); err != nil {
+		return nil, err
+	}
+	return &decimalNumber{new(big.Rat).Mul(d.value, right)}, nil
+}
+
+func (d *decimalNumber) Div(other Number) (Number, error) {
+	divisor := other.(*decimalNumber).value
+	if divisor.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	if err := checkRatOperation(d.value, divisor); err != nil {
+		return nil, err
+	}
+	return &decimalNumber{new(big.Rat).Quo(d.value, divisor)}, nil
+}
+
+func (d *decimalNumber) Pow(other Number) (Number, error) {
+	result, err := ratPow(d.value, other.(*decimalNumber).value)
+	if err != nil {
+		return nil, err
+	}
+	return &decimalNumber{result}, nil
+}
+
+func (d *decimalNumber) Mod(other Number) (Number, error) {
+	result, err := ratMod(d.value, other.(*decimalNumber).value)
+	if err != nil {
+		return nil, err
+	}
+	return &decimalNumber{result}, nil
+}
+
+func (d *decimalNumber) Neg() (Number, error) {
+	return &decimalNumber{new(big.Rat).Neg(d.value)}, nil
+}
+
+// Sqrt is exact for perfect squares and otherwise approximated well beyond
+// the DecimalPlaces that are rendered.
+func (d *decimalNumber) Sqrt() (Number, error) {
+	if d.value.Sign() < 0 {
+		return nil, ErrDomain
+	}
+	if root, ok := ratSqrt(d.value); ok {
+		return &decimalNumber{root}, nil
+	}
+
+	root := new(big.Float).SetPrec(sqrtPrecision).SetRat(d.value)
+	root.Sqrt(root)
+
+	result, _ := root.Rat(nil)
+	return &decimalNumber{result}, nil
+}
+
+func (d *decimalNumber) Cmp(other Number) int {
+	return d.value.Cmp(other.(*decimalNumber).value)
+}
+
+func (d *decimalNumber) String() string {
+	str := d.value.FloatString(DecimalPlaces)
+	if strings.Contains(str, ".") {
+		str = strings.TrimRight(str, "0")
+		str = strings.TrimSuffix(str, ".")
+	}
+	if str == "-0" {
+		str = "0"
+	}
+	return str
+}
+
+type rationalNumber struct {
+	value *big.Rat
+}
+
+func (r *rationalNumber) Add(other Number) (Number, error) {
+	right := other.(*rationalNumber).value
+	if err := checkRatOperation(r.value, right); err != nil {
+		return nil, err
+	}
+	return &rationalNumber{new(big.Rat).Add(r.value, right)}, nil
+}
+
+func (r *rationalNumber) Sub(other Number) (Number, error) {
+	right := other.(*rationalNumber).value
+	if err := checkRatOperation(r.value, right); err != nil {
+		return nil, err
+	}
+	return &rationalNumber{new(big.Rat).Sub(r.value, right)}, nil
+}
+
+func (r *rationalNumber) Mul(other Number) (Number, error) {
+	right := other.(*rationalNumber).value
+	if err := checkRatOperation(r.value, right); err != nil {
+		return nil, err
+	}
+	return &rationalNumber{new(big.Rat).Mul(r.value, right)}, nil
+}
+
+func (r *rationalNumber) Div(other Number) (Number, error) {
+	divisor := other.(*rationalNumber).value
+	if divisor.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	if err := checkRatOperation(r.value, divisor); err != nil {
+		return nil, err
+	}
+	return &rationalNumber{new(big.Rat).Quo(r.value, divisor)}, nil
+}
+
+func (r *rationalNumber) Pow(other Number) (Number, error) {
+	result, err := ratPow(r.value, other.(*rationalNumber).value)
+	if err != nil {
+		return nil, err
+	}
+	return &rationalNumber{result}, nil
+}
+
+func (r *rationalNumber) Mod(other Number) (Number, error) {
+	result, err := ratMod(r.value, other.(*rationalNumber).value)
+	if err != nil {
+		return nil, err
+	}
+	return &rationalNumber{result}, nil
+}
+
+func (r *rationalNumber) Neg() (Number, error) {
+	return &rationalNumber{new(big.Rat).Neg(r.value)}, nil
+}
+
+// Sqrt fails with ErrDomain when the root is irrational, since it cannot be
+// represented exactly.
+func (r *rationalNumber) Sqrt() (Number, error) {
+	if r.value.Sign() < 0 {
+		return nil, ErrDomain
+	}
+	root, ok := ratSqrt(r.value)
+	if !ok {
+		return nil, ErrDomain
+	}
+	return &rationalNumber{root}, nil
+}
+
+func (r *rationalNumber) Cmp(other Number) int {
+	return r.value.Cmp(other.(*rationalNumber).value)
+}
+
+func (r *rationalNumber) String() string {
+	return r.value.RatString()
+}
+
+type bigIntNumber struct {
+	value *big.Int
+}
+
+func (b *bigIntNumber) Add(other Number) (Number, error) {
+	return &bigIntNumber{new(big.Int).Add(b.value, other.(*bigIntNumber).value)}, nil
+}
+
+func (b *bigIntNumber) Sub(other Number) (Number, error) {
+	return &bigIntNumber{new(big.Int).Sub(b.value, other.(*bigIntNumber).value)}, nil
+}
+
+func (b *bigIntNumber) Mul(other Number) (Number, error) {
+	right := other.(*bigIntNumber).value
+	if b.value.BitLen()+right.BitLen() > MaxBits {
+		return nil, ErrOverflow
+	}
+	return &bigIntNumber{new(big.Int).Mul(b.value, right)}, nil
+}
+
+func (b *bigIntNumber) Div(other Number) (Number, error) {
+	divisor := other.(*bigIntNumber).value
+	if divisor.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return &bigIntNumber{new(big.Int).Quo(b.value, divisor)}, nil
+}
+
+// Pow truncates results with a negative exponent towards zero, the same way
+// integer division does.
+func (b *bigIntNumber) Pow(other Number) (Number, error) {
+	exponent := other.(*bigIntNumber).value
+	if exponent.Sign() < 0 {
+		switch {
+		case b.value.Sign() == 0:
+			return nil, ErrDivisionByZero
+		case b.value.CmpAbs(big.NewInt(1)) != 0:
+			return &bigIntNumber{big.NewInt(0)}, nil
+		case b.value.Sign() < 0 && exponent.Bit(0) == 1:
+			return &bigIntNumber{big.NewInt(-1)}, nil
+		default:
+			return &bigIntNumber{big.NewInt(1)}, nil
+		}
+	}
+
+	if err := checkPower(b.value, exponent); err != nil {
+		return nil, err
+	}
+	return &bigIntNumber{new(big.Int).Exp(b.value, exponent, nil)}, nil
+}
+
+func (b *bigIntNumber) Mod(other Number) (Number, error) {
+	divisor := other.(*bigIntNumber).value
+	if divisor.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return &bigIntNumber{new(big.Int).Rem(b.value, divisor)}, nil
+}
+
+func (b *bigIntNumber) Neg() (Number, error) {
+	return &bigIntNumber{new(big.Int).Neg(b.value)}, nil
+}
+
+// Sqrt returns the integer square root, i.e. the result rounded down.
+func (b *bigIntNumber) Sqrt() (Number, error) {
+	if b.value.Sign() < 0 {
+		return nil, ErrDomain
+	}
+	return &bigIntNumber{new(big.Int).Sqrt(b.value)}, nil
+}
+
+func (b *bigIntNumber) Cmp(other Number) int {
+	return b.value.Cmp(other.(*bigIntNumber).value)
+}
+
+func (b *bigIntNumber) String() string {
+	return b.value.String()
+}
+
+// ratPow raises base to an integer exponent. Fractional exponents generally
+// produce irrational results, so they are rejected with ErrDomain.
+func ratPow(base, exponent *big.Rat) (*big.Rat, error) {
+	if !exponent.IsInt() {
+		return nil, ErrDomain
+	}
+
+	power := new(big.Int).Abs(exponent.Num())
+	if base.Sign() == 0 {
+		if exponent.Sign() < 0 {
+			return nil, ErrDivisionByZero
+		}
+		if power.Sign() == 0 {
+			return big.NewRat(1, 1), nil
+		}
+		return new(big.Rat), nil
+	}
+
+	if err := checkPower(base.Num(), power); err != nil {
+		return nil, err
+	}
+	if err := checkPower(base.Denom(), power); err != nil {
+		return nil, err
+	}
+
+	num := new(big.Int).Exp(base.Num(), power, nil)
+	denom := new(big.Int).Exp(base.Denom(), power, nil)
+
+	result := new(big.Rat).SetFrac(num, denom)
+	if exponent.Sign() < 0 {
+		result.Inv(result)
+	}
+	return result, nil
+}
+
+// checkPower reports ErrOverflow when base raised to a non-negative
+// exponent could take more than MaxBits, estimating the size of the power
+// as the size of the base times the exponent.
+func checkPower(base, exponent *big.Int) error {
+	if base.CmpAbs(big.NewInt(1)) <= 0 {
+		return nil
+	}
+
+	bits := new(big.Int).Mul(big.NewInt(int64(base.BitLen())), exponent)
+	if bits.Cmp(big.NewInt(int64(MaxBits))) > 0 {
+		return ErrOverflow
+	}
+	return nil
+}
+
+// checkRatOperation reports ErrOverflow when adding, subtracting,
+// multiplying or dividing two rationals could give a numerator or a
+// denominator of more than MaxBits.
+func checkRatOperation(left, right *big.Rat) error {
+	bits := max(left.Num().BitLen(), left.Denom().BitLen()) + max(right.Num().BitLen(), right.Denom().BitLen())
+	if bits > MaxBits {
+		return ErrOverflow
+	}
+	return nil
+}
+
+// ratMod returns the remainder of truncated division, matching the sign
+// convention of the integer arithmetics.
+func ratMod(left, right *big.Rat) (*big.Rat, error) {
+	if right.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	quotient := new(big.Rat).Quo(left, right)
+	truncated := new(big.Int).Quo(quotient.Num(), quotient.Denom())
+
+	product := new(big.Rat).Mul(right, new(big.Rat).SetInt(truncated))
+	return new(big.Rat).Sub(left, product), nil
+}
+
+// ratSqrt returns the exact square root of a non-negative rational, if it
+// has one.
+func ratSqrt(value *big.Rat) (*big.Rat, bool) {
+	num := new(big.Int).Sqrt(value.Num())
+	denom := new(big.Int).Sqrt(value.Denom())
+
+	if new(big.Int).Mul(num, num).Cmp(value.Num()) != 0 ||
+		new(big.Int).Mul(denom, denom).Cmp(value.Denom()) != 0 {
+		return nil, false
+	}
+	return new(big.Rat).SetFrac(num, denom), true
+}
+
+// exactValue returns the exact value of a number. A float is read as the
+// shortest decimal that rounds to it, so 0.1 stays one tenth.
+func exactValue(number Number) *big.Rat {
+	switch n := number.(type) {
+	case intNumber:
+		return new(big.Rat).SetInt64(int64(n))
+	case floatNumber:
+		value, ok := new(big.Rat).SetString(n.String())
+		if !ok {
+			return new(big.Rat)
+		}
+		return value
+	case *decimalNumber:
+		return new(big.Rat).Set(n.value)
+	case *rationalNumber:
+		return new(big.Rat).Set(n.value)
+	case *bigIntNumber:
+		return new(big.Rat).SetInt(n.value)
+	default:
+		return new(big.Rat)
+	}
+}
+
+// exactNumber converts an exact value to the arithmetic being used. The
+// integer arithmetics report a value with a fractional part as ErrDomain
+// rather than truncating it.
+func exactNumber(value *big.Rat, arithmetic Arithmetic) (Number, error) {
+	switch arithmetic {
+	case ArithmeticFloat:
+		num, _ := value.Float64()
+		if math.IsInf(num, 0) {
+			return nil, ErrOverflow
+		}
+		return floatNumber(num), nil
+	case ArithmeticDecimal:
+		return &decimalNumber{value}, nil
+	case ArithmeticRational:
+		return &rationalNumber{value}, nil
+	}
+
+	if !value.IsInt() {
+		return nil, ErrDomain
+	}
+	if arithmetic == ArithmeticBigInteger {
+		return &bigIntNumber{new(big.Int).Set(value.Num())}, nil
+	}
+	if !value.Num().IsInt64() {
+		return nil, ErrOverflow
+	}
+	return intNumber(value.Num().Int64()), nil
+}
@@ -0,0 +1,437 @@
+package interp
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// TensOrder describes how a language combines a multiple of ten with a digit.
+type TensOrder int
+
+const (
+	// TensThenOnes reads "twenty-three" or "twenty three".
+	TensThenOnes TensOrder = iota
+	// TensConjunctionOnes reads "двадесет и три".
+	TensConjunctionOnes
+	// OnesConjunctionTens reads "dreiundzwanzig".
+	OnesConjunctionTens
+)
+
+// NumberWords is the vocabulary the lexer uses to read spelled-out cardinals
+// in one language.
+//
+// Hundreds holds words that stand for a whole multiple of a hundred, e.g. the
+// Bulgarian "двеста", while Hundred is the word that multiplies the number
+// before it by a hundred. Implicit lists the multipliers that may stand
+// without a number before them, as the German "tausend" does. When Compound
+// is set, numbers are also recognised when their words are written together,
+// e.g. "dreihundertfünf".
+type NumberWords struct {
+	Zero        string
+	Ones        map[string]int64
+	Teens       map[string]int64
+	Tens        map[string]int64
+	Hundreds    map[string]int64
+	Hundred     string
+	Scales      map[string]int64
+	Implicit    map[string]bool
+	Conjunction string
+	TensOrder   TensOrder
+	Compound    bool
+}
+
+var numberWordRegex = regexp.MustCompile(`^(\s+|-)?([\p{L}\p{N}_]+)`)
+
+type numberWord struct {
+	separator string
+	value     string
+	end       int
+
+	// joined marks a word written together with the one before it, and
+	// wordEnd a word that isn't followed by another joined word.
+	joined  bool
+	wordEnd bool
+}
+
+// numberWordFinder finds the longest spelled-out cardinal at the start of the
+// input, e.g. "one hundred and five" in "one hundred and five plus 3?".
+// Words that don't continue the number, such as a second "five" in
+// "five five", are left for the next token.
+type numberWordFinder struct {
+	vocabulary *NumberWords
+}
+
+func (n numberWordFinder) FindString(input string) string {
+	_, end, ok := n.vocabulary.parse(input)
+	if !ok {
+		return ""
+	}
+	return input[:end]
+}
+
+// newToken converts a spelled-out number to a NumberToken holding its
+// digits.
+func (n numberWordFinder) newToken(value string, pos Position) Token {
+	number, _, _ := n.vocabulary.parse(value)
+
+	return &NumberToken{
+		Value:    strconv.FormatInt(number, 10),
+		Position: pos,
+	}
+}
+
+// parse reads the longest cardinal at the start of the input and returns its
+// value along with the number of bytes it spans. A number that ends in the
+// middle of a compound word is cut back to the last whole word.
+func (n *NumberWords) parse(input string) (int64, int, bool) {
+	words := n.split(input)
+
+	for {
+		value, count, ok := parseNumberWords(words, n)
+		if !ok {
+			return 0, 0, false
+		}
+
+		last := words[count-1]
+		if last.wordEnd {
+			return value, last.end, true
+		}
+
+		for count > 0 && words[count-1].joined {
+			count--
+		}
+		words = words[:count-1]
+	}
+}
+
+// split breaks the start of the input into the words a cardinal there could
+// be made of. It scans one word at a time and stops at the first word that
+// isn't a number word, or once there are more words than a cardinal can
+// span, so that finding a number costs no more than the number itself.
+func (n *NumberWords) split(input string) []numberWord {
+	words := []numberWord{}
+	maxWords := n.maxWords()
+
+	offset := 0
+	for len(words) < maxWords {
+		match := numberWordRegex.FindStringSubmatchIndex(input[offset:])
+		if match == nil {
+			return words
+		}
+
+		separator := ""
+		if match[2] >= 0 {
+			separator = input[offset+match[2] : offset+match[3]]
+		}
+		start := offset + match[4]
+		value := input[start : offset+match[5]]
+
+		parts := []string{value}
+		if n.Compound {
+			if compound, ok := n.splitCompound(value); ok {
+				parts = compound
+			}
+		}
+		if len(parts) == 1 && !n.isWord(value) {
+			return words
+		}
+
+		for i, part := range parts {
+			start += len(part)
+
+			word := numberWord{
+				value:   part,
+				end:     start,
+				joined:  i > 0,
+				wordEnd: i == len(parts)-1,
+			}
+			if i == 0 {
+				word.separator = separator
+			}
+			words = append(words, word)
+		}
+
+		offset += match[1]
+	}
+
+	return words
+}
+
+// maxWords bounds the number of words in a cardinal. Each scale, and the
+// number below the smallest one, takes at most a conjunction, "three hundred
+// and twenty-one" or its like, and the scale word itself.
+func (n *NumberWords) maxWords() int {
+	return 8 * (len(n.Scales) + 1)
+}
+
+// splitCompound breaks a word into the number words it is made of, preferring
+// the longest word at every step, e.g. "dreiundzwanzig" into "drei", "und"
+// and "zwanzig".
+func (n *NumberWords) splitCompound(word string) ([]string, bool) {
+	return n.splitCompoundFrom(word, 0, n.longestWord(), map[int]bool{})
+}
+
+// splitCompoundFrom splits word[start:], remembering the offsets the rest of
+// the word can't be split from. Words such as "dreizehn", which is also
+// "drei" and "zehn", can be split in more than one way, and without the
+// memo a compound of many of them that fails to split at its end would try
+// every combination.
+func (n *NumberWords) splitCompoundFrom(word string, start, longest int, failed map[int]bool) ([]string, bool) {
+	if start == len(word) {
+		return []string{}, true
+	}
+	if failed[start] {
+		return nil, false
+	}
+
+	for end := min(len(word), start+longest); end > start; end-- {
+		if !n.isWord(word[start:end]) {
+			continue
+		}
+
+		if rest, ok := n.splitCompoundFrom(word, end, longest, failed); ok {
+			return append([]string{word[start:end]}, rest...), true
+		}
+	}
+
+	failed[start] = true
+	return nil, false
+}
+
+// longestWord returns the length in bytes of the longest number word.
+func (n *NumberWords) longestWord() int {
+	longest := max(len(n.Zero), len(n.Hundred), len(n.Conjunction))
+	for _, words := range []map[string]int64{n.Ones, n.Teens, n.Tens, n.Hundreds, n.Scales} {
+		for word := range words {
+			longest = max(longest, len(word))
+		}
+	}
+	return longest
+}
+
+func (n *NumberWords) isWord(word string) bool {
+	if word == n.Zero || word == n.Hundred || word == n.Conjunction {
+		return true
+	}
+
+	for _, words := range []map[string]int64{n.Ones, n.Teens, n.Tens, n.Hundreds, n.Scales} {
+		if _, ok := words[word]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// numberWordParser reads a cardinal out of a sequence of words. Every method
+// returns false without consuming anything when the words at the current
+// position don't form the requested part of the number.
+type numberWordParser struct {
+	vocabulary *NumberWords
+	words      []numberWord
+	pos        int
+}
+
+// parseNumberWords returns the value of the cardinal at the start of words
+// along with the number of words it spans.
+func parseNumberWords(words []numberWord, vocabulary *NumberWords) (int64, int, bool) {
+	p := numberWordParser{vocabulary: vocabulary, words: words}
+
+	if p.accept(vocabulary.Zero) {
+		return 0, p.pos, true
+	}
+
+	var total int64
+	count := 0
+	lastScale := int64(0)
+
+	for {
+		start := p.pos
+		if total > 0 && !p.acceptConjunction() {
+			p.pos = start
+		}
+
+		groupStart := p.pos
+		group, ok := p.parseGroup()
+		if !ok {
+			p.pos = start
+			break
+		}
+
+		scale, ok := p.peekScale()
+		if ok && (lastScale == 0 || scale < lastScale) {
+			p.pos++
+			total += group * scale
+			lastScale = scale
+			count = p.pos
+			continue
+		}
+
+		if p.pos == groupStart {
+			p.pos = start
+			break
+		}
+
+		total += group
+		count = p.pos
+		break
+	}
+
+	return total, count, count > 0
+}
+
+// parseGroup reads the number that a scale word multiplies. A scale that may
+// stand on its own, such as "хиляда", counts as one of itself.
+func (p *numberWordParser) parseGroup() (int64, bool) {
+	if value, ok := p.parseHundreds(); ok {
+		return value, true
+	}
+
+	word, ok := p.peek()
+	if ok && p.vocabulary.Implicit[word.value] {
+		if _, ok := p.vocabulary.Scales[word.value]; ok {
+			return 1, true
+		}
+	}
+
+	return 0, false
+}
+
+// parseHundreds reads a number below one thousand, e.g. "three hundred and
+// forty-two".
+func (p *numberWordParser) parseHundreds() (int64, bool) {
+	value, ok := p.acceptFrom(p.vocabulary.Hundreds)
+	if !ok {
+		value, ok = p.parseTens()
+		if !ok && p.isImplicit(p.vocabulary.Hundred) {
+			value, ok = 1, true
+		}
+		if !ok {
+			return 0, false
+		}
+
+		if value >= 10 || !p.accept(p.vocabulary.Hundred) {
+			return value, true
+		}
+		value *= 100
+	}
+
+	start := p.pos
+	p.acceptConjunction()
+	rest, ok := p.parseTens()
+	if !ok {
+		p.pos = start
+		return value, true
+	}
+
+	return value + rest, true
+}
+
+// parseTens reads a number below one hundred, e.g. "seven", "fifteen" or
+// "twenty-three".
+func (p *numberWordParser) parseTens() (int64, bool) {
+	if value, ok := p.acceptFrom(p.vocabulary.Teens); ok {
+		return value, true
+	}
+
+	if p.vocabulary.TensOrder == OnesConjunctionTens {
+		ones, ok := p.acceptFrom(p.vocabulary.Ones)
+		if !ok {
+			return p.acceptFrom(p.vocabulary.Tens)
+		}
+
+		start := p.pos
+		if p.acceptConjunction() {
+			if tens, ok := p.acceptFrom(p.vocabulary.Tens); ok {
+				return ones + tens, true
+			}
+		}
+		p.pos = start
+
+		return ones, true
+	}
+
+	if value, ok := p.acceptFrom(p.vocabulary.Ones); ok {
+		return value, true
+	}
+
+	value, ok := p.acceptFrom(p.vocabulary.Tens)
+	if !ok {
+		return 0, false
+	}
+
+	start := p.pos
+	if p.vocabulary.TensOrder == TensConjunctionOnes && !p.acceptConjunction() {
+		return value, true
+	}
+
+	if ones, ok := p.acceptFrom(p.vocabulary.Ones); ok {
+		return value + ones, true
+	}
+	p.pos = start
+
+	return value, true
+}
+
+// acceptConjunction consumes the "and" in "one hundred and five".
+func (p *numberWordParser) acceptConjunction() bool {
+	return p.accept(p.vocabulary.Conjunction)
+}
+
+func (p *numberWordParser) accept(value string) bool {
+	word, ok := p.peek()
+	if !ok || value == "" || word.value != value {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *numberWordParser) acceptFrom(words map[string]int64) (int64, bool) {
+	word, ok := p.peek()
+	if !ok {
+		return 0, false
+	}
+
+	value, ok := words[word.value]
+	if ok {
+		p.pos++
+	}
+	return value, ok
+}
+
+func (p *numberWordParser) isImplicit(value string) bool {
+	word, ok := p.peek()
+	return ok && value != "" && word.value == value && p.vocabulary.Implicit[value]
+}
+
+func (p *numberWordParser) peekScale() (int64, bool) {
+	word, ok := p.peek()
+	if !ok {
+		return 0, false
+	}
+	scale, ok := p.vocabulary.Scales[word.value]
+	return scale, ok
+}
+
+// peek returns the word at the current position. Only the first word of a
+// number may stand at the start of the input, and hyphens are only allowed
+// between a multiple of ten and a digit, as in "twenty-three".
+func (p *numberWordParser) peek() (numberWord, bool) {
+	if p.pos >= len(p.words) {
+		return numberWord{}, false
+	}
+
+	word := p.words[p.pos]
+	if (p.pos == 0) != (word.separator == "" && !word.joined) {
+		return numberWord{}, false
+	}
+	if word.separator == "-" {
+		_, isTens := p.vocabulary.Tens[p.words[p.pos-1].value]
+		_, isOnes := p.vocabulary.Ones[word.value]
+		if !isTens || !isOnes {
+			return numberWord{}, false
+		}
+	}
+
+	return word, true
+}
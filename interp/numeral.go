@@ -0,0 +1,180 @@
+package interp
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// NumeralSystem is the way a number is written. Literals can be written in
+// any of them, and "in hexadecimal" or "in Roman numerals" at the end of a
+// statement writes its result in another one.
+type NumeralSystem int
+
+const (
+	Decimal NumeralSystem = iota
+	Hexadecimal
+	Octal
+	Binary
+	Roman
+)
+
+// radixes lists the prefix and the base of the numeral systems that write
+// numbers with digits.
+var radixes = map[NumeralSystem]struct {
+	prefix string
+	base   int
+}{
+	Hexadecimal: {"0x", 16},
+	Octal:       {"0o", 8},
+	Binary:      {"0b", 2},
+}
+
+var radixLiteralRegex = regexp.MustCompile(`^-?0(x[0-9a-f]+|o[0-7]+|b[01]+)`)
+
+// romanLiteralRegex only matches numerals in their canonical subtractive
+// form, e.g. "xiv" but not "xiiii", that don't run into another letter.
+var romanLiteralRegex = regexp.MustCompile(`^m{0,3}(cm|cd|d?c{0,3})(xc|xl|l?x{0,3})(ix|iv|v?i{0,3})\b`)
+
+var romanDigits = []struct {
+	value  int64
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"},
+	{1, "I"},
+}
+
+// maxRoman is the largest number that can be written in Roman numerals
+// without a bar over its digits.
+const maxRoman = 3999
+
+// radixFinder finds a hexadecimal, octal or binary literal at the start of
+// the input, e.g. "0x1f", "0o17" or "0b101".
+type radixFinder struct{}
+
+func (r radixFinder) FindString(input string) string {
+	return radixLiteralRegex.FindString(input)
+}
+
+// romanFinder finds a Roman numeral of at least two letters at the start of
+// the input. A single letter such as "x" is left for the identifiers, so
+// that it can still name a variable.
+type romanFinder struct{}
+
+func (r romanFinder) FindString(input string) string {
+	literal := romanLiteralRegex.FindString(input)
+	if len(literal) < 2 {
+		return ""
+	}
+	return literal
+}
+
+// NewNumeralToken converts a hexadecimal, octal, binary or Roman literal to
+// a NumberToken holding its decimal digits.
+func NewNumeralToken(value string, pos Position) Token {
+	number, _ := parseNumeral(value)
+
+	return &NumberToken{
+		Value:    number.String(),
+		Position: pos,
+	}
+}
+
+// parseNumeral reads a hexadecimal, octal, binary or Roman literal in any
+// case. The second result is false for any other text.
+func parseNumeral(literal string) (*big.Int, bool) {
+	literal = strings.ToLower(literal)
+
+	if radixLiteralRegex.FindString(literal) == literal {
+		negative := strings.HasPrefix(literal, "-")
+		literal = strings.TrimPrefix(literal, "-")
+
+		number, ok := new(big.Int).SetString(literal, 0)
+		if !ok {
+			return nil, false
+		}
+		if negative {
+			number.Neg(number)
+		}
+		return number, true
+	}
+
+	if literal != "" && romanLiteralRegex.FindString(literal) == literal {
+		return big.NewInt(parseRoman(literal)), true
+	}
+
+	return nil, false
+}
+
+func parseRoman(literal string) int64 {
+	literal = strings.ToUpper(literal)
+
+	var value int64
+	for _, digit := range romanDigits {
+		for strings.HasPrefix(literal, digit.symbol) {
+			value += digit.value
+			literal = literal[len(digit.symbol):]
+		}
+	}
+	return value
+}
+
+func formatRoman(value int64) string {
+	var builder strings.Builder
+	for _, digit := range romanDigits {
+		for value >= digit.value {
+			builder.WriteString(digit.symbol)
+			value -= digit.value
+		}
+	}
+	return builder.String()
+}
+
+// numeral is a number written in a numeral system other than the decimal
+// one. It is only ever the result of a statement, so its operations are
+// those of the number it writes.
+type numeral struct {
+	Number
+	system NumeralSystem
+}
+
+// inNumeralSystem writes a number in a numeral system. Only whole numbers can
+// be written in another system than the decimal one, so fractions are
+// reported as ErrDomain, as are numbers that have no Roman numeral.
+// Quantities and dates are reported as ErrDimensionMismatch.
+func inNumeralSystem(number Number, system NumeralSystem) (Number, error) {
+	if unitOf(number) != nil || isDate(number) {
+		return nil, ErrDimensionMismatch
+	}
+	if system == Decimal {
+		return number, nil
+	}
+
+	value := exactValue(number)
+	if !value.IsInt() {
+		return nil, ErrDomain
+	}
+	if system == Roman && (value.Sign() <= 0 || value.Num().Cmp(big.NewInt(maxRoman)) > 0) {
+		return nil, ErrDomain
+	}
+
+	return &numeral{Number: number, system: system}, nil
+}
+
+// String writes a hexadecimal number as "0x1f", an octal one as "0o17", a
+// binary one as "0b101" and a Roman one as "XLII".
+func (n *numeral) String() string {
+	value := exactValue(n.Number).Num()
+	if n.system == Roman {
+		return formatRoman(value.Int64())
+	}
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	radix := radixes[n.system]
+	return sign + radix.prefix + new(big.Int).Abs(value).Text(radix.base)
+}
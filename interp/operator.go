@@ -0,0 +1,389 @@
+package interp
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OperatorRegistry holds the operators known to the lexer, the parser and
+// the interpreter, along with the locale whose words the lexer reads.
+type OperatorRegistry struct {
+	operators          []Operator
+	locale             *Locale
+	suggestionDistance int
+}
+
+// NewOperatorRegistry returns a registry that holds the built-in operators
+// and reads English.
+func NewOperatorRegistry() *OperatorRegistry {
+	registry := &OperatorRegistry{
+		operators:          []Operator{},
+		locale:             English,
+		suggestionDistance: DefaultSuggestionDistance,
+	}
+
+	for _, operator := range builtinOperators {
+		if err := registry.Register(operator); err != nil {
+			panic(err)
+		}
+	}
+
+	return registry
+}
+
+// Register adds an operator to the registry. A phrase can be used once as an
+// infix operator and once as either a prefix, a postfix or a function
+// operator, the way "minus" is both subtraction and negation.
+func (o *OperatorRegistry) Register(operator Operator) error {
+	operator.Phrase = strings.Join(strings.Fields(strings.ToLower(operator.Phrase)), " ")
+
+	if operator.Phrase == "" || operator.Apply == nil ||
+		operator.Notation < Infix || operator.Notation > Function ||
+		(operator.Notation != Postfix && operator.Notation != Function && operator.Precedence < 1) ||
+		(operator.Percentage && operator.Notation != Postfix) ||
+		(operator.Relative && operator.Notation != Infix) {
+		return ErrInvalidOperator
+	}
+
+	for _, existing := range o.operators {
+		if existing.Phrase != operator.Phrase {
+			continue
+		}
+		if existing.Notation == operator.Notation ||
+			(existing.Notation != Infix && operator.Notation != Infix) {
+			return ErrDuplicateOperator
+		}
+	}
+
+	o.operators = append(o.operators, operator)
+	return nil
+}
+
+// Localise returns a copy of the registry that reads the given locale. Every
+// operator the locale translates is registered under each of its translated
+// phrases instead of its own.
+func (o *OperatorRegistry) Localise(locale *Locale) (*OperatorRegistry, error) {
+	registry := &OperatorRegistry{
+		operators:          []Operator{},
+		locale:             locale,
+		suggestionDistance: o.suggestionDistance,
+	}
+
+	for _, operator := range o.operators {
+		phrases, ok := locale.Operators[operator.Phrase]
+		if !ok {
+			phrases = []string{operator.Phrase}
+		}
+
+		for _, phrase := range phrases {
+			operator.Phrase = phrase
+			if err := registry.Register(operator); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return registry, nil
+}
+
+// SetSuggestionDistance sets the largest edit distance at which the lexer
+// suggests a known word in place of one it can't read, e.g. "plus" for
+// "plsu". A distance of zero turns the suggestions off. Registries returned
+// by Localise keep the distance.
+func (o *OperatorRegistry) SetSuggestionDistance(distance int) {
+	o.suggestionDistance = distance
+}
+
+func (o *OperatorRegistry) lookup(phrase string, notation Notation) (Operator, bool) {
+	for _, operator := range o.operators {
+		if operator.Phrase == phrase && operator.Notation == notation {
+			return operator, true
+		}
+	}
+	return Operator{}, false
+}
+
+func (o *OperatorRegistry) lookupUnary(phrase string) (Operator, bool) {
+	if operator, ok := o.lookup(phrase, Prefix); ok {
+		return operator, true
+	}
+	return o.lookup(phrase, Postfix)
+}
+
+func (o *OperatorRegistry) has(phrase string, notation Notation) bool {
+	_, ok := o.lookup(phrase, notation)
+	return ok
+}
+
+// FindString returns the longest operator phrase at the start of the input,
+// which lets the registry act as the lexer's PatternFinder for operands.
+func (o *OperatorRegistry) FindString(input string) string {
+	longest := ""
+	for _, operator := range o.operators {
+		if len(operator.Phrase) > len(longest) && hasPhrasePrefix(input, operator.Phrase) {
+			longest = operator.Phrase
+		}
+	}
+	return longest
+}
+
+var defaultOperators = NewOperatorRegistry()
+
+var builtinOperators = []Operator{
+	{Phrase: "plus", Notation: Infix, Precedence: 1, Relative: true, Apply: binaryOperator(Number.Add)},
+	{Phrase: "minus", Notation: Infix, Precedence: 1, Relative: true, Apply: binaryOperator(Number.Sub)},
+	{Phrase: "increased by", Notation: Infix, Precedence: 1, Relative: true, Apply: binaryOperator(Number.Add)},
+	{Phrase: "decreased by", Notation: Infix, Precedence: 1, Relative: true, Apply: binaryOperator(Number.Sub)},
+	{Phrase: "multiplied by", Notation: Infix, Precedence: 2, Apply: binaryOperator(Number.Mul)},
+	{Phrase: "divided by", Notation: Infix, Precedence: 2, Apply: binaryOperator(Number.Div)},
+	{Phrase: "modulo", Notation: Infix, Precedence: 2, Apply: binaryOperator(Number.Mod)},
+	{Phrase: "percent of", Notation: Infix, Precedence: 2, Apply: percentOf},
+	{Phrase: "raised to the power of", Notation: Infix, Precedence: 3, Associativity: RightAssociative, Apply: binaryOperator(Number.Pow)},
+	{Phrase: "minus", Notation: Prefix, Precedence: 3, Apply: unaryOperator(Number.Neg)},
+	{Phrase: "the square root of", Notation: Prefix, Precedence: 3, Apply: unaryOperator(Number.Sqrt)},
+	{Phrase: "squared", Notation: Postfix, Apply: powerOperator("2")},
+	{Phrase: "cubed", Notation: Postfix, Apply: powerOperator("3")},
+	{Phrase: "percent", Notation: Postfix, Percentage: true, Apply: percent},
+	{Phrase: "the sum of", Notation: Function, Apply: sum},
+	{Phrase: "the product of", Notation: Function, Apply: product},
+	{Phrase: "the average of", Notation: Function, Apply: average},
+	{Phrase: "the minimum of", Notation: Function, Apply: extreme(-1)},
+	{Phrase: "the maximum of", Notation: Function, Apply: extreme(1)},
+	{Phrase: "the median of", Notation: Function, Apply: median},
+}
+
+func binaryOperator(apply func(Number, Number) (Number, error)) OperatorFunc {
+	return func(operands []Number, arithmetic Arithmetic) (Number, error) {
+		return apply(operands[0], operands[1])
+	}
+}
+
+func unaryOperator(apply func(Number) (Number, error)) OperatorFunc {
+	return func(operands []Number, arithmetic Arithmetic) (Number, error) {
+		return apply(operands[0])
+	}
+}
+
+func powerOperator(exponent string) OperatorFunc {
+	return func(operands []Number, arithmetic Arithmetic) (Number, error) {
+		power, err := ParseNumber(exponent, arithmetic)
+		if err != nil {
+			return nil, err
+		}
+
+		return operands[0].Pow(power)
+	}
+}
+
+// percent and percentOf are evaluated exactly whatever the arithmetic. In
+// the arithmetics that approximate percentages, a percentage is kept as an
+// exact decimal until it is combined with something, so that "200
+// multiplied by 10 percent" is 20 even in integer arithmetic. Only the value
+// it is combined into is settled in the arithmetic, so "15 percent of 10"
+// is 1 in integer arithmetic, truncated the way integer division is.
+func percent(operands []Number, arithmetic Arithmetic) (Number, error) {
+	return percentage(operands[0], nil, arithmetic)
+}
+
+func percentOf(operands []Number, arithmetic Arithmetic) (Number, error) {
+	return percentage(operands[0], operands[1], arithmetic)
+}
+
+// percentage returns rate hundredths of whole, or of one when whole is nil.
+// A percentage of a quantity is measured in its unit, while the rate itself
+// can't have a unit.
+func percentage(rate, whole Number, arithmetic Arithmetic) (Number, error) {
+	if isDate(rate) || (whole != nil && isDate(whole)) {
+		return nil, ErrDimensionMismatch
+	}
+	if q, ok := rate.(*quantity); ok {
+		if q.unit != nil {
+			return nil, ErrDimensionMismatch
+		}
+		rate = q.value
+	}
+
+	result := new(big.Rat).Mul(exactValue(rate), big.NewRat(1, 100))
+
+	var unit *Unit
+	if whole != nil {
+		if q, ok := whole.(*quantity); ok {
+			whole, unit = q.value, q.unit
+		}
+		result.Mul(result, exactValue(whole))
+	}
+
+	var number Number = &decimalNumber{result}
+	if !approximates(arithmetic) {
+		var err error
+		number, err = exactNumber(result, arithmetic)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newQuantity(number, unit, arithmetic), nil
+}
+
+// approximates reports whether an arithmetic can't represent every
+// percentage exactly, so that percentages are kept as exact decimals in it.
+func approximates(arithmetic Arithmetic) bool {
+	return arithmetic == ArithmeticInteger || arithmetic == ArithmeticBigInteger || arithmetic == ArithmeticFloat
+}
+
+// hasExactOperand reports whether any of the operands is an exact decimal
+// kept in an arithmetic that approximates percentages.
+func hasExactOperand(operands []Number, arithmetic Arithmetic) bool {
+	if !approximates(arithmetic) {
+		return false
+	}
+
+	for _, operand := range operands {
+		if q, ok := operand.(*quantity); ok {
+			operand = q.value
+		}
+		if _, ok := operand.(*decimalNumber); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyOperator applies an operator to its operands. When one of them is an
+// exact decimal kept for a percentage, the operation is carried out in
+// decimal arithmetic and only its result is settled in the arithmetic of
+// the expression.
+func applyOperator(operator Operator, operands []Number, arithmetic Arithmetic) (Number, error) {
+	if !hasExactOperand(operands, arithmetic) {
+		return operator.Apply(withUnits(operands), arithmetic)
+	}
+
+	result, err := operator.Apply(withUnits(inDecimal(operands)), ArithmeticDecimal)
+	if err != nil {
+		return nil, err
+	}
+	return settle(result, arithmetic)
+}
+
+// inDecimal converts numbers to decimal arithmetic, keeping their units.
+func inDecimal(numbers []Number) []Number {
+	converted := make([]Number, len(numbers))
+	for i, number := range numbers {
+		switch n := number.(type) {
+		case *quantity:
+			converted[i] = &quantity{value: inDecimal([]Number{n.value})[0], unit: n.unit, arithmetic: ArithmeticDecimal}
+		case *date:
+			converted[i] = &date{time: n.time, arithmetic: ArithmeticDecimal}
+		default:
+			converted[i] = &decimalNumber{exactValue(number)}
+		}
+	}
+	return converted
+}
+
+// settle converts a result computed in decimal arithmetic to the arithmetic
+// of the expression. The integer arithmetics truncate a result with a
+// fractional part towards zero, the same way integer division does.
+func settle(number Number, arithmetic Arithmetic) (Number, error) {
+	switch n := number.(type) {
+	case *quantity:
+		value, err := settle(n.value, arithmetic)
+		if err != nil {
+			return nil, err
+		}
+		return &quantity{value: value, unit: n.unit, arithmetic: arithmetic}, nil
+	case *date:
+		return &date{time: n.time, arithmetic: arithmetic}, nil
+	case *decimalNumber:
+		switch arithmetic {
+		case ArithmeticDecimal:
+			return n, nil
+		case ArithmeticInteger, ArithmeticBigInteger:
+			truncated := new(big.Int).Quo(n.value.Num(), n.value.Denom())
+			return exactNumber(new(big.Rat).SetInt(truncated), arithmetic)
+		default:
+			return exactNumber(n.value, arithmetic)
+		}
+	default:
+		return number, nil
+	}
+}
+
+func sum(operands []Number, arithmetic Arithmetic) (Number, error) {
+	return fold(operands, Number.Add)
+}
+
+func product(operands []Number, arithmetic Arithmetic) (Number, error) {
+	return fold(operands, Number.Mul)
+}
+
+func fold(operands []Number, apply func(Number, Number) (Number, error)) (Number, error) {
+	result := operands[0]
+	for _, operand := range operands[1:] {
+		var err error
+		result, err = apply(result, operand)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// average divides the sum of its operands by their count, so it truncates
+// in integer arithmetic the same way division does.
+func average(operands []Number, arithmetic Arithmetic) (Number, error) {
+	total, err := sum(operands, arithmetic)
+	if err != nil {
+		return nil, err
+	}
+
+	return divideByCount(total, len(operands), arithmetic)
+}
+
+// extreme returns the operand that compares as sign to all the others, i.e.
+// the maximum for 1 and the minimum for -1.
+func extreme(sign int) OperatorFunc {
+	return func(operands []Number, arithmetic Arithmetic) (Number, error) {
+		if !haveSameDimension(operands) {
+			return nil, ErrDimensionMismatch
+		}
+
+		result := operands[0]
+		for _, operand := range operands[1:] {
+			if operand.Cmp(result) == sign {
+				result = operand
+			}
+		}
+		return result, nil
+	}
+}
+
+// median returns the middle operand, or the average of the two middle ones
+// when their count is even.
+func median(operands []Number, arithmetic Arithmetic) (Number, error) {
+	if !haveSameDimension(operands) {
+		return nil, ErrDimensionMismatch
+	}
+
+	sorted := append([]Number{}, operands...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle], nil
+	}
+
+	return average(sorted[middle-1:middle+1], arithmetic)
+}
+
+func divideByCount(total Number, count int, arithmetic Arithmetic) (Number, error) {
+	divisor, err := ParseNumber(strconv.Itoa(count), arithmetic)
+	if err != nil {
+		return nil, err
+	}
+
+	return total.Div(divisor)
+}
@@ -0,0 +1,211 @@
+package interp_test
+
+import (
+	"testing"
+
+	"github.com/VitoNaychev/eval-web-service/interp"
+	"github.com/VitoNaychev/eval-web-service/testutil/assert"
+)
+
+func TestOperatorRegistry(t *testing.T) {
+	averagedWith := interp.Operator{
+		Phrase:     "averaged with",
+		Notation:   interp.Infix,
+		Precedence: 2,
+		Apply: func(operands []interp.Number, arithmetic interp.Arithmetic) (interp.Number, error) {
+			sum, err := operands[0].Add(operands[1])
+			if err != nil {
+				return nil, err
+			}
+
+			two, err := interp.ParseNumber("2", arithmetic)
+			if err != nil {
+				return nil, err
+			}
+
+			return sum.Div(two)
+		},
+	}
+	doubled := interp.Operator{
+		Phrase:   "doubled",
+		Notation: interp.Postfix,
+		Apply: func(operands []interp.Number, arithmetic interp.Arithmetic) (interp.Number, error) {
+			return operands[0].Add(operands[0])
+		},
+	}
+	theNegationOf := interp.Operator{
+		Phrase:     "the negation of",
+		Notation:   interp.Prefix,
+		Precedence: 3,
+		Apply: func(operands []interp.Number, arithmetic interp.Arithmetic) (interp.Number, error) {
+			return operands[0].Neg()
+		},
+	}
+	less := interp.Operator{
+		Phrase:        "less",
+		Notation:      interp.Infix,
+		Precedence:    1,
+		Associativity: interp.RightAssociative,
+		Apply: func(operands []interp.Number, arithmetic interp.Arithmetic) (interp.Number, error) {
+			return operands[0].Sub(operands[1])
+		},
+	}
+
+	operators := interp.NewOperatorRegistry()
+	for _, operator := range []interp.Operator{averagedWith, doubled, theNegationOf, less} {
+		assert.RequireNoError(t, operators.Register(operator))
+	}
+
+	cases := []struct {
+		Name           string
+		Input          string
+		LeftToRight    bool
+		ExpectedResult string
+	}{
+		{
+			Name:           "custom infix operator",
+			Input:          "What is 4 averaged with 8?",
+			ExpectedResult: "6",
+		},
+		{
+			Name:           "custom infix operator binds by its precedence",
+			Input:          "What is 2 plus 4 averaged with 8?",
+			ExpectedResult: "8",
+		},
+		{
+			Name:           "custom infix operator in left-to-right mode",
+			Input:          "What is 2 plus 4 averaged with 8?",
+			LeftToRight:    true,
+			ExpectedResult: "7",
+		},
+		{
+			Name:           "custom right-associative operator",
+			Input:          "What is 10 less 4 less 3?",
+			ExpectedResult: "9",
+		},
+		{
+			Name:           "custom postfix operator",
+			Input:          "What is 3 doubled plus 1?",
+			ExpectedResult: "7",
+		},
+		{
+			Name:           "custom prefix operator",
+			Input:          "What is the negation of 3 plus 5?",
+			ExpectedResult: "2",
+		},
+		{
+			Name:           "built-in operators keep working",
+			Input:          "What is 2 raised to the power of 3 squared?",
+			ExpectedResult: "512",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := operators.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			parse := operators.Parse
+			if test.LeftToRight {
+				parse = operators.ParseLeftToRight
+			}
+
+			tree, err := parse(tokens)
+			assert.RequireNoError(t, err)
+
+			result, err := operators.Interpret(tree, interp.Options{})
+			assert.RequireNoError(t, err)
+
+			assert.Equal(t, result.String(), test.ExpectedResult)
+		})
+	}
+
+	t.Run("doesn't change the default operators", func(t *testing.T) {
+		_, err := interp.Lex("What is 4 averaged with 8?")
+
+		assert.ErrorIs(t, err, interp.ErrUnsupportedOperation)
+	})
+}
+
+func TestOperatorRegistryRegister(t *testing.T) {
+	apply := func(operands []interp.Number, arithmetic interp.Arithmetic) (interp.Number, error) {
+		return operands[0], nil
+	}
+
+	cases := []struct {
+		Name          string
+		Operator      interp.Operator
+		ExpectedError error
+	}{
+		{
+			Name:          "new phrase",
+			Operator:      interp.Operator{Phrase: "Averaged  With", Notation: interp.Infix, Precedence: 2, Apply: apply},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "new notation of an existing phrase",
+			Operator:      interp.Operator{Phrase: "plus", Notation: interp.Prefix, Precedence: 3, Apply: apply},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "empty phrase",
+			Operator:      interp.Operator{Phrase: " ", Notation: interp.Infix, Precedence: 1, Apply: apply},
+			ExpectedError: interp.ErrInvalidOperator,
+		},
+		{
+			Name:          "missing implementation",
+			Operator:      interp.Operator{Phrase: "averaged with", Notation: interp.Infix, Precedence: 1},
+			ExpectedError: interp.ErrInvalidOperator,
+		},
+		{
+			Name:          "unknown notation",
+			Operator:      interp.Operator{Phrase: "averaged with", Notation: interp.Notation(7), Precedence: 1, Apply: apply},
+			ExpectedError: interp.ErrInvalidOperator,
+		},
+		{
+			Name:          "infix operator without precedence",
+			Operator:      interp.Operator{Phrase: "averaged with", Notation: interp.Infix, Apply: apply},
+			ExpectedError: interp.ErrInvalidOperator,
+		},
+		{
+			Name:          "percentage that isn't postfix",
+			Operator:      interp.Operator{Phrase: "per mille", Notation: interp.Prefix, Precedence: 3, Percentage: true, Apply: apply},
+			ExpectedError: interp.ErrInvalidOperator,
+		},
+		{
+			Name:          "relative operator that isn't infix",
+			Operator:      interp.Operator{Phrase: "the increase of", Notation: interp.Function, Relative: true, Apply: apply},
+			ExpectedError: interp.ErrInvalidOperator,
+		},
+		{
+			Name:          "function without precedence",
+			Operator:      interp.Operator{Phrase: "the mode of", Notation: interp.Function, Apply: apply},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "function form of a prefix operator",
+			Operator:      interp.Operator{Phrase: "the square root of", Notation: interp.Function, Apply: apply},
+			ExpectedError: interp.ErrDuplicateOperator,
+		},
+		{
+			Name:          "existing phrase and notation",
+			Operator:      interp.Operator{Phrase: "Multiplied By", Notation: interp.Infix, Precedence: 2, Apply: apply},
+			ExpectedError: interp.ErrDuplicateOperator,
+		},
+		{
+			Name:          "postfix form of a prefix operator",
+			Operator:      interp.Operator{Phrase: "minus", Notation: interp.Postfix, Apply: apply},
+			ExpectedError: interp.ErrDuplicateOperator,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			operators := interp.NewOperatorRegistry()
+
+			gotError := operators.Register(test.Operator)
+
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+		})
+	}
+}
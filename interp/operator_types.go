@@ -0,0 +1,55 @@
+package interp
+
+import "errors"
+
+// Notation describes where an operator stands relative to its operands.
+// Infix operators take two operands, prefix and postfix operators take one.
+// Function operators stand before a list of one or more operands separated
+// by commas or "and", as in "the sum of 1, 2 and 3".
+type Notation int
+
+const (
+	Infix Notation = iota
+	Prefix
+	Postfix
+	Function
+)
+
+type Associativity int
+
+const (
+	LeftAssociative Associativity = iota
+	RightAssociative
+)
+
+// OperatorFunc applies an operator to its operands, which are given in the
+// order they appear in the expression.
+type OperatorFunc func(operands []Number, arithmetic Arithmetic) (Number, error)
+
+// Operator describes an operation that can be used in an expression.
+//
+// Precedence decides how tightly an infix operator binds relative to other
+// infix operators; higher values bind tighter. For a prefix operator it is
+// the precedence at which its operand is read, so "minus 2 raised to the
+// power of 2" negates the power. Postfix operators always apply to the term
+// right before them, and functions apply to their list of arguments, so the
+// Precedence and Associativity of both are ignored.
+//
+// Percentage marks a postfix operator that reads its operand as a number of
+// hundredths. When such a term is the right operand of an infix operator
+// marked Relative, it is taken of the left operand instead, so "200 plus 10
+// percent" is 220 rather than 200.1.
+type Operator struct {
+	Phrase        string
+	Notation      Notation
+	Precedence    int
+	Associativity Associativity
+	Percentage    bool
+	Relative      bool
+	Apply         OperatorFunc
+}
+
+var (
+	ErrInvalidOperator   = errors.New("invalid operator")
+	ErrDuplicateOperator = errors.New("duplicate operator")
+)
@@ -2,10 +2,37 @@ package interp
 
 import "github.com/VitoNaychev/eval-web-service/sm"
 
-func Parse(tokens []Token) ([]Token, error) {
+// Parse validates the token sequence and builds an expression tree in which
+// "raised to the power of" binds tighter than "multiplied by", "divided by" and
+// "modulo", which in turn bind tighter than "plus" and "minus". Powers are
+// right-associative, every other operation is left-associative.
+func Parse(tokens []Token) (Node, error) {
+	return defaultOperators.Parse(tokens)
+}
+
+// ParseLeftToRight builds an expression tree that folds operations strictly
+// from left to right, preserving the semantics of the original interpreter.
+func ParseLeftToRight(tokens []Token) (Node, error) {
+	return defaultOperators.ParseLeftToRight(tokens)
+}
+
+// Parse builds an expression tree that follows the precedence and
+// associativity of the operators held by the registry.
+func (o *OperatorRegistry) Parse(tokens []Token) (Node, error) {
+	return o.parse(tokens, standardPrecedence)
+}
+
+// ParseLeftToRight builds an expression tree that folds the operators held by
+// the registry strictly from left to right.
+func (o *OperatorRegistry) ParseLeftToRight(tokens []Token) (Node, error) {
+	return o.parse(tokens, leftToRightPrecedence)
+}
+
+func (o *OperatorRegistry) parse(tokens []Token, precedence operandPrecedence) (Node, error) {
 	ctx := ParserContext{
 		InputTokens:  tokens,
 		OutputTokens: []Token{},
+		Operators:    o,
 	}
 	parser := sm.New(stateParserInitial, parserDeltas, &ctx)
 
@@ -13,14 +40,36 @@ func Parse(tokens []Token) ([]Token, error) {
 		var err error
 
 		switch token.(type) {
-		case *QuestionToken:
+		case *QuestionToken, *YesNoQuestionToken:
 			err = parser.Exec(eventParserQuestion)
-		case *NumberToken:
+		case *NumberToken, *DateToken, *PreviousResultToken:
 			err = parser.Exec(eventParserNumber)
 		case *OperandToken:
 			err = parser.Exec(eventParserOperand)
 		case *PunctuationToken:
 			err = parser.Exec(eventParserPunctuation)
+		case *OpenGroupToken:
+			err = parser.Exec(eventParserOpenGroup)
+		case *CloseGroupToken:
+			err = parser.Exec(eventParserCloseGroup)
+		case *AssignmentToken:
+			err = parser.Exec(eventParserAssignment)
+		case *BindingToken:
+			err = parser.Exec(eventParserBinding)
+		case *IdentifierToken:
+			err = parser.Exec(eventParserIdentifier)
+		case *SeparatorToken:
+			err = parser.Exec(eventParserSeparator)
+		case *ComparisonToken:
+			err = parser.Exec(eventParserComparison)
+		case *ConjunctionToken:
+			err = parser.Exec(eventParserConjunction)
+		case *UnitToken:
+			err = parser.Exec(eventParserUnit)
+		case *ConversionToken:
+			err = parser.Exec(eventParserConversion)
+		case *NumeralSystemToken:
+			err = parser.Exec(eventParserNumeralSystem)
 		}
 
 		if err != nil {
@@ -29,8 +78,298 @@ func Parse(tokens []Token) ([]Token, error) {
 	}
 
 	if parser.Current != stateParserFinal {
-		return nil, ErrInvalidSyntax
+		err := ErrInvalidSyntax
+		if ctx.GroupDepth > 0 {
+			err = ErrUnclosedGroup
+		}
+
+		expected := expectedTokens(parser.Current, &ctx)
+		return nil, NewDiagnostic(err, endOfInput(tokens), "", expected)
+	}
+
+	builder := treeBuilder{
+		tokens:     ctx.OutputTokens,
+		operators:  o,
+		precedence: precedence,
+	}
+
+	return builder.buildStatement(), nil
+}
+
+func endOfInput(tokens []Token) Position {
+	if len(tokens) == 0 {
+		return Position{}
+	}
+
+	last := tokens[len(tokens)-1].GetPosition()
+	return Position{Offset: last.Offset + last.Length}
+}
+
+// operandPrecedence describes how tightly operands bind while the expression
+// tree is built. Higher values bind tighter.
+type operandPrecedence struct {
+	infix            func(Operator) int
+	rightAssociative func(Operator) bool
+	prefix           func(Operator) int
+}
+
+var standardPrecedence = operandPrecedence{
+	infix: func(operator Operator) int {
+		return operator.Precedence
+	},
+	rightAssociative: func(operator Operator) bool {
+		return operator.Associativity == RightAssociative
+	},
+	prefix: func(operator Operator) int {
+		return operator.Precedence
+	},
+}
+
+var leftToRightPrecedence = operandPrecedence{
+	infix: func(operator Operator) int {
+		return 1
+	},
+	rightAssociative: func(operator Operator) bool {
+		return false
+	},
+	prefix: func(operator Operator) int {
+		return 2
+	},
+}
+
+// treeBuilder turns the significant tokens emitted by the parser state machine
+// into an expression tree using precedence climbing. The state machine has
+// already validated the sequence, so the builder assumes it is well formed.
+type treeBuilder struct {
+	tokens     []Token
+	operators  *OperatorRegistry
+	precedence operandPrecedence
+}
+
+func (t *treeBuilder) buildStatement() Node {
+	if _, ok := t.tokens[0].(*AssignmentToken); !ok {
+		return t.buildConversion(t.buildCondition(0))
+	}
+
+	identifier := t.tokens[1].(*IdentifierToken)
+	t.tokens = t.tokens[2:]
+
+	return &AssignmentNode{
+		Identifier: identifier,
+		Value:      t.buildConversion(t.buildExpression(0)),
+	}
+}
+
+// buildConversion wraps an expression in the conversion that ends the
+// statement, if there is one.
+func (t *treeBuilder) buildConversion(node Node) Node {
+	if len(t.tokens) == 0 {
+		return node
+	}
+
+	conversion, ok := t.tokens[0].(*ConversionToken)
+	if !ok {
+		return node
+	}
+
+	if system, ok := t.tokens[1].(*NumeralSystemToken); ok {
+		t.tokens = t.tokens[2:]
+
+		return &NumeralSystemNode{
+			Conversion:    conversion,
+			NumeralSystem: system,
+			Expression:    node,
+		}
+	}
+
+	unit := t.tokens[1].(*UnitToken)
+	t.tokens = t.tokens[2:]
+
+	return &ConversionNode{
+		Conversion: conversion,
+		Unit:       unit,
+		Expression: node,
+	}
+}
+
+// buildCondition joins the comparisons of a yes/no question by their
+// conjunctions, binding "and" tighter than "or". Any other statement is a
+// single expression, which it returns as is.
+func (t *treeBuilder) buildCondition(minPrecedence int) Node {
+	left := t.buildComparison()
+
+	for len(t.tokens) > 0 {
+		conjunction, ok := t.tokens[0].(*ConjunctionToken)
+		if !ok {
+			break
+		}
+
+		kind, _ := t.operators.locale.conjunction(conjunction.GetToken().(string))
+
+		precedence := kind.precedence()
+		if precedence < minPrecedence {
+			break
+		}
+		t.tokens = t.tokens[1:]
+
+		right := t.buildCondition(precedence + 1)
+		left = &ConjunctionNode{
+			Conjunction: conjunction,
+			Left:        left,
+			Right:       right,
+		}
+	}
+
+	return left
+}
+
+func (t *treeBuilder) buildComparison() Node {
+	left := t.buildExpression(0)
+	if len(t.tokens) == 0 {
+		return left
+	}
+
+	comparison, ok := t.tokens[0].(*ComparisonToken)
+	if !ok {
+		return left
+	}
+	t.tokens = t.tokens[1:]
+
+	return &ComparisonNode{
+		Comparison: comparison,
+		Left:       left,
+		Right:      t.buildExpression(0),
+	}
+}
+
+func (t *treeBuilder) buildExpression(minPrecedence int) Node {
+	left := t.buildOperand()
+
+	for len(t.tokens) > 0 {
+		operand, ok := t.tokens[0].(*OperandToken)
+		if !ok {
+			break
+		}
+
+		operator, _ := t.operators.lookup(operand.GetToken().(string), Infix)
+
+		precedence := t.precedence.infix(operator)
+		if precedence < minPrecedence {
+			break
+		}
+		t.tokens = t.tokens[1:]
+
+		nextPrecedence := precedence + 1
+		if t.precedence.rightAssociative(operator) {
+			nextPrecedence = precedence
+		}
+
+		right := t.buildExpression(nextPrecedence)
+		left = &BinaryNode{
+			Operand: operand,
+			Left:    left,
+			Right:   right,
+		}
+	}
+
+	return left
+}
+
+func (t *treeBuilder) buildOperand() Node {
+	if operand, ok := t.tokens[0].(*OperandToken); ok {
+		t.tokens = t.tokens[1:]
+		if t.operators.has(operand.GetToken().(string), Function) {
+			return t.buildFunction(operand)
+		}
+
+		operator, _ := t.operators.lookup(operand.GetToken().(string), Prefix)
+
+		return &UnaryNode{
+			Operand: operand,
+			Child:   t.buildExpression(t.precedence.prefix(operator)),
+		}
+	}
+
+	var node Node
+	if _, ok := t.tokens[0].(*OpenGroupToken); ok {
+		t.tokens = t.tokens[1:]
+		node = t.buildExpression(0)
+		t.tokens = t.tokens[1:]
+	} else if previous, ok := t.tokens[0].(*PreviousResultToken); ok {
+		t.tokens = t.tokens[1:]
+
+		node = &PreviousResultNode{
+			Reference: previous,
+		}
+	} else if date, ok := t.tokens[0].(*DateToken); ok {
+		t.tokens = t.tokens[1:]
+
+		node = &DateNode{
+			Date: date,
+		}
+	} else if identifier, ok := t.tokens[0].(*IdentifierToken); ok {
+		t.tokens = t.tokens[1:]
+
+		node = &VariableNode{
+			Identifier: identifier,
+		}
+	} else {
+		number := t.tokens[0].(*NumberToken)
+		t.tokens = t.tokens[1:]
+
+		node = &NumberNode{
+			Number: number,
+		}
+	}
+
+	return t.buildPostfix(node)
+}
+
+// buildFunction reads the arguments of a function. Each argument is a term,
+// so an infix operand after one applies to the result of the function.
+func (t *treeBuilder) buildFunction(function *OperandToken) Node {
+	arguments := []Node{t.buildOperand()}
+
+	for len(t.tokens) > 0 {
+		if _, ok := t.tokens[0].(*SeparatorToken); !ok {
+			break
+		}
+		t.tokens = t.tokens[1:]
+
+		arguments = append(arguments, t.buildOperand())
+	}
+
+	return &FunctionNode{
+		Function:  function,
+		Arguments: arguments,
+	}
+}
+
+// buildPostfix applies the unit and the postfix operands that follow a term
+// to it.
+func (t *treeBuilder) buildPostfix(node Node) Node {
+	for len(t.tokens) > 0 {
+		if unit, ok := t.tokens[0].(*UnitToken); ok {
+			t.tokens = t.tokens[1:]
+
+			node = &QuantityNode{
+				Unit:  unit,
+				Child: node,
+			}
+			continue
+		}
+
+		operand, ok := t.tokens[0].(*OperandToken)
+		if !ok || !t.operators.has(operand.GetToken().(string), Postfix) {
+			break
+		}
+		t.tokens = t.tokens[1:]
+
+		node = &UnaryNode{
+			Operand: operand,
+			Child:   node,
+		}
 	}
 
-	return ctx.OutputTokens, nil
+	return node
 }
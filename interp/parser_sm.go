@@ -1,6 +1,10 @@
 package interp
 
-import "github.com/VitoNaychev/eval-web-service/sm"
+import (
+	"fmt"
+
+	"github.com/VitoNaychev/eval-web-service/sm"
+)
 
 const (
 	stateParserInitial = iota
@@ -10,6 +14,14 @@ const (
 	stateParserPunctuation
 	stateParserFinal
 	stateParserSyntaxError
+	stateParserOpenGroup
+	stateParserCloseGroup
+	stateParserPrefix
+	stateParserPostfix
+	stateParserAssignment
+	stateParserAssignmentTarget
+	stateParserConversion
+	stateParserConverted
 )
 
 type ParserEvent int
@@ -20,6 +32,17 @@ const (
 	eventParserOperand
 	eventParserPunctuation
 	eventParserInvalid
+	eventParserOpenGroup
+	eventParserCloseGroup
+	eventParserAssignment
+	eventParserBinding
+	eventParserIdentifier
+	eventParserSeparator
+	eventParserComparison
+	eventParserConjunction
+	eventParserUnit
+	eventParserConversion
+	eventParserNumeralSystem
 )
 
 func SignificantTokenCallback(delta sm.Delta, ctx sm.Context) error {
@@ -41,43 +64,556 @@ func NonsignificanTokenCallback(delta sm.Delta, ctx sm.Context) error {
 	return nil
 }
 
+// QuestionCallback reads the phrase that opens a question and remembers
+// whether it is a yes/no question, whose comparisons are read differently.
+func QuestionCallback(delta sm.Delta, ctx sm.Context) error {
+	parserCtx := ctx.(*ParserContext)
+	_, parserCtx.YesNo = parserCtx.InputTokens[0].(*YesNoQuestionToken)
+
+	return NonsignificanTokenCallback(delta, ctx)
+}
+
 func SyntaxErrorCallback(delta sm.Delta, ctx sm.Context) error {
-	return ErrInvalidSyntax
+	return unexpectedTokenError(ErrInvalidSyntax, delta, ctx)
+}
+
+func OpenGroupCallback(delta sm.Delta, ctx sm.Context) error {
+	parserCtx := ctx.(*ParserContext)
+	parserCtx.GroupDepth++
+
+	return SignificantTokenCallback(delta, ctx)
+}
+
+func CloseGroupCallback(delta sm.Delta, ctx sm.Context) error {
+	parserCtx := ctx.(*ParserContext)
+	closeArguments(parserCtx)
+	parserCtx.GroupDepth--
+
+	return SignificantTokenCallback(delta, ctx)
+}
+
+// FunctionCallback opens the argument list of a function, e.g. the "the sum
+// of" in "What is the sum of 1, 2 and 3?".
+func FunctionCallback(delta sm.Delta, ctx sm.Context) error {
+	parserCtx := ctx.(*ParserContext)
+	parserCtx.Arguments = append(parserCtx.Arguments, parserCtx.GroupDepth)
+
+	return SignificantTokenCallback(delta, ctx)
+}
+
+// InfixOperandCallback reads an infix operand, which ends the argument lists
+// opened in the current group, so in "the sum of 1 and 2 plus 3" the "plus"
+// adds 3 to the sum.
+func InfixOperandCallback(delta sm.Delta, ctx sm.Context) error {
+	closeArguments(ctx.(*ParserContext))
+
+	return SignificantTokenCallback(delta, ctx)
+}
+
+// ComparisonCallback reads the comparison of a yes/no question, which ends
+// the argument lists of its left side.
+func ComparisonCallback(delta sm.Delta, ctx sm.Context) error {
+	parserCtx := ctx.(*ParserContext)
+	closeArguments(parserCtx)
+	parserCtx.Compared = true
+
+	return SignificantTokenCallback(delta, ctx)
+}
+
+// ConjunctionCallback joins the comparison that has been read with the one
+// that follows it.
+func ConjunctionCallback(delta sm.Delta, ctx sm.Context) error {
+	parserCtx := ctx.(*ParserContext)
+	closeArguments(parserCtx)
+	parserCtx.Compared = false
+
+	return SignificantTokenCallback(delta, ctx)
+}
+
+// ConversionCallback reads the phrase that converts the result of the
+// statement to a unit, which ends the argument lists that are still open.
+func ConversionCallback(delta sm.Delta, ctx sm.Context) error {
+	closeArguments(ctx.(*ParserContext))
+
+	return SignificantTokenCallback(delta, ctx)
+}
+
+// SeparatingConjunctionCallback reads a conjunction that separates the
+// arguments of a function, e.g. the "and" in "the sum of 1 and 2", as a
+// separator.
+func SeparatingConjunctionCallback(delta sm.Delta, ctx sm.Context) error {
+	parserCtx := ctx.(*ParserContext)
+
+	currentToken := parserCtx.InputTokens[0]
+	parserCtx.InputTokens = parserCtx.InputTokens[1:]
+
+	separator := NewSeparatorToken(currentToken.GetToken().(string), currentToken.GetPosition())
+	parserCtx.OutputTokens = append(parserCtx.OutputTokens, separator)
+
+	return nil
+}
+
+func closeArguments(parserCtx *ParserContext) {
+	for len(parserCtx.Arguments) > 0 && parserCtx.Arguments[len(parserCtx.Arguments)-1] == parserCtx.GroupDepth {
+		parserCtx.Arguments = parserCtx.Arguments[:len(parserCtx.Arguments)-1]
+	}
+}
+
+func UnclosedGroupCallback(delta sm.Delta, ctx sm.Context) error {
+	return unexpectedTokenError(ErrUnclosedGroup, delta, ctx)
+}
+
+func UnopenedGroupCallback(delta sm.Delta, ctx sm.Context) error {
+	return unexpectedTokenError(ErrUnopenedGroup, delta, ctx)
+}
+
+func EmptyGroupCallback(delta sm.Delta, ctx sm.Context) error {
+	return unexpectedTokenError(ErrEmptyGroup, delta, ctx)
+}
+
+// unexpectedTokenError points err at the token being read, along with the
+// tokens that would have been accepted in its place.
+func unexpectedTokenError(err error, delta sm.Delta, ctx sm.Context) error {
+	parserCtx := ctx.(*ParserContext)
+	token := parserCtx.InputTokens[0]
+
+	text := fmt.Sprint(token.GetToken())
+	expected := expectedTokens(delta.Current, parserCtx)
+
+	return NewDiagnostic(err, token.GetPosition(), text, expected)
+}
+
+// expectedTokens lists the kinds of tokens accepted in the given state.
+func expectedTokens(state sm.State, parserCtx *ParserContext) []string {
+	switch state {
+	case stateParserInitial:
+		return []string{"question", "assignment"}
+	case stateParserQuestion, stateParserOperand, stateParserOpenGroup, stateParserPrefix:
+		return []string{"number", "identifier", "prefix operand", "function", "open group"}
+	case stateParserAssignment:
+		return []string{"identifier"}
+	case stateParserAssignmentTarget:
+		return []string{"binding"}
+	case stateParserConversion:
+		return []string{"unit", "numeral system"}
+	case stateParserConverted:
+		return []string{"punctuation"}
+	case stateParserNumber, stateParserCloseGroup, stateParserPostfix:
+		expected := []string{"operand"}
+		if state != stateParserPostfix {
+			expected = append(expected, "unit")
+		}
+		if isInsideArguments(parserCtx) {
+			expected = append(expected, "separator")
+		}
+		if parserCtx.GroupDepth > 0 {
+			return append(expected, "close group")
+		}
+		if parserCtx.YesNo && !parserCtx.Compared {
+			return append(expected, "comparison")
+		}
+		if parserCtx.YesNo {
+			expected = append(expected, "conjunction")
+		} else {
+			expected = append(expected, "conversion")
+		}
+		return append(expected, "punctuation")
+	default:
+		return []string{"end of input"}
+	}
+}
+
+func isInsideGroup(delta sm.Delta, ctx sm.Context) (bool, error) {
+	parserCtx := ctx.(*ParserContext)
+
+	return parserCtx.GroupDepth > 0, nil
+}
+
+func isOutsideGroup(delta sm.Delta, ctx sm.Context) (bool, error) {
+	isInsideGroup, err := isInsideGroup(delta, ctx)
+	return !isInsideGroup, err
+}
+
+// isComplete reports whether the statement can end after the term that has
+// been read, i.e. no group is open and a yes/no question has compared its
+// sides.
+func isComplete(delta sm.Delta, ctx sm.Context) (bool, error) {
+	parserCtx := ctx.(*ParserContext)
+
+	return parserCtx.GroupDepth == 0 && (!parserCtx.YesNo || parserCtx.Compared), nil
+}
+
+func isIncompleteComparison(delta sm.Delta, ctx sm.Context) (bool, error) {
+	parserCtx := ctx.(*ParserContext)
+
+	return parserCtx.GroupDepth == 0 && parserCtx.YesNo && !parserCtx.Compared, nil
+}
+
+// isComparable reports whether the term that has been read can be compared,
+// i.e. it ends the left side of a comparison in a yes/no question. Groups
+// hold numbers, so comparisons can't be made inside them.
+func isComparable(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return isIncompleteComparison(delta, ctx)
+}
+
+func isNotComparable(delta sm.Delta, ctx sm.Context) (bool, error) {
+	isComparable, err := isComparable(delta, ctx)
+	return !isComparable, err
+}
+
+// isJoinable reports whether the term that has been read ends a comparison
+// that a conjunction can join with the next one.
+func isJoinable(delta sm.Delta, ctx sm.Context) (bool, error) {
+	parserCtx := ctx.(*ParserContext)
+
+	return !isInsideArguments(parserCtx) && parserCtx.GroupDepth == 0 &&
+		parserCtx.YesNo && parserCtx.Compared, nil
+}
+
+func isNotJoinableOrSeparating(delta sm.Delta, ctx sm.Context) (bool, error) {
+	isJoinable, err := isJoinable(delta, ctx)
+	return !isJoinable && !isInsideArguments(ctx.(*ParserContext)), err
+}
+
+// isConvertible reports whether the statement can be converted to a unit
+// after the term that has been read. Only the result of a whole statement
+// can be converted, and the answer to a yes/no question has no unit.
+func isConvertible(delta sm.Delta, ctx sm.Context) (bool, error) {
+	parserCtx := ctx.(*ParserContext)
+
+	return parserCtx.GroupDepth == 0 && !parserCtx.YesNo, nil
+}
+
+func isNotConvertible(delta sm.Delta, ctx sm.Context) (bool, error) {
+	isConvertible, err := isConvertible(delta, ctx)
+	return !isConvertible, err
+}
+
+func isInsideArguments(parserCtx *ParserContext) bool {
+	arguments := parserCtx.Arguments
+	return len(arguments) > 0 && arguments[len(arguments)-1] == parserCtx.GroupDepth
+}
+
+// isInsideArgumentList reports whether the term that has been read is an
+// argument of a function whose list can be continued with a separator.
+func isInsideArgumentList(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return isInsideArguments(ctx.(*ParserContext)), nil
+}
+
+func isOutsideArgumentList(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return !isInsideArguments(ctx.(*ParserContext)), nil
+}
+
+func isCurrentOperand(ctx sm.Context, notation Notation) bool {
+	parserCtx := ctx.(*ParserContext)
+
+	operand, ok := parserCtx.InputTokens[0].(*OperandToken)
+	if !ok {
+		return false
+	}
+
+	return parserCtx.Operators.has(operand.GetToken().(string), notation)
+}
+
+// isPrefixOperand reports whether the operand being read applies to the term
+// that follows it, e.g. the "minus" in "What is minus 3 plus 5?".
+func isPrefixOperand(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return isCurrentOperand(ctx, Prefix), nil
+}
+
+func isFunctionOperand(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return isCurrentOperand(ctx, Function), nil
+}
+
+func isNotPrefixOrFunctionOperand(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return !isCurrentOperand(ctx, Prefix) && !isCurrentOperand(ctx, Function), nil
+}
+
+func isInfixOperand(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return isCurrentOperand(ctx, Infix), nil
+}
+
+func isPostfixOperand(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return isCurrentOperand(ctx, Postfix), nil
+}
+
+func isNotInfixOrPostfixOperand(delta sm.Delta, ctx sm.Context) (bool, error) {
+	return !isCurrentOperand(ctx, Infix) && !isCurrentOperand(ctx, Postfix), nil
 }
 
 var parserDeltas = []sm.Delta{
-	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserQuestion), Predicate: nil, Callback: NonsignificanTokenCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserQuestion), Predicate: nil, Callback: QuestionCallback},
 	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserAssignment), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserInitial), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 
 	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserNumber), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserOpenGroup), Predicate: nil, Callback: OpenGroupCallback},
 	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
-	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPrefix), Predicate: isPrefixOperand, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPrefix), Predicate: isFunctionOperand, Callback: FunctionCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: isNotPrefixOrFunctionOperand, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: UnopenedGroupCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserNumber), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserQuestion), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 
-	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserOperand), Predicate: nil, Callback: SignificantTokenCallback},
-	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserFinal), Predicate: nil, Callback: NonsignificanTokenCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPostfix), Predicate: isPostfixOperand, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserOperand), Predicate: isInfixOperand, Callback: InfixOperandCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: isNotInfixOrPostfixOperand, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserFinal), Predicate: isComplete, Callback: NonsignificanTokenCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: isInsideGroup, Callback: UnclosedGroupCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: isIncompleteComparison, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserCloseGroup), Predicate: isInsideGroup, Callback: CloseGroupCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: isOutsideGroup, Callback: UnopenedGroupCallback},
 	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserOperand), Predicate: isInsideArgumentList, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: isOutsideArgumentList, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserOperand), Predicate: isComparable, Callback: ComparisonCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: isNotComparable, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserOperand), Predicate: isInsideArgumentList, Callback: SeparatingConjunctionCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserOperand), Predicate: isJoinable, Callback: ConjunctionCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: isNotJoinableOrSeparating, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserPostfix), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserConversion), Predicate: isConvertible, Callback: ConversionCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: isNotConvertible, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserNumber), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 
 	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserNumber), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserOpenGroup), Predicate: nil, Callback: OpenGroupCallback},
 	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
-	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPrefix), Predicate: isPrefixOperand, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPrefix), Predicate: isFunctionOperand, Callback: FunctionCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: isNotPrefixOrFunctionOperand, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserNumber), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOperand), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserNumber), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserOpenGroup), Predicate: nil, Callback: OpenGroupCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: EmptyGroupCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPrefix), Predicate: isPrefixOperand, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPrefix), Predicate: isFunctionOperand, Callback: FunctionCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: isNotPrefixOrFunctionOperand, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: UnclosedGroupCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserNumber), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserOpenGroup), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPostfix), Predicate: isPostfixOperand, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserOperand), Predicate: isInfixOperand, Callback: InfixOperandCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: isNotInfixOrPostfixOperand, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserCloseGroup), Predicate: isInsideGroup, Callback: CloseGroupCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: isOutsideGroup, Callback: UnopenedGroupCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserFinal), Predicate: isComplete, Callback: NonsignificanTokenCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: isInsideGroup, Callback: UnclosedGroupCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: isIncompleteComparison, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserOperand), Predicate: isInsideArgumentList, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: isOutsideArgumentList, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserOperand), Predicate: isComparable, Callback: ComparisonCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: isNotComparable, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserOperand), Predicate: isInsideArgumentList, Callback: SeparatingConjunctionCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserOperand), Predicate: isJoinable, Callback: ConjunctionCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: isNotJoinableOrSeparating, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserPostfix), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserConversion), Predicate: isConvertible, Callback: ConversionCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: isNotConvertible, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserCloseGroup), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserNumber), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserOpenGroup), Predicate: nil, Callback: OpenGroupCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPrefix), Predicate: isPrefixOperand, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPrefix), Predicate: isFunctionOperand, Callback: FunctionCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: isNotPrefixOrFunctionOperand, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserNumber), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPrefix), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserPostfix), Predicate: isPostfixOperand, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserOperand), Predicate: isInfixOperand, Callback: InfixOperandCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: isNotInfixOrPostfixOperand, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserFinal), Predicate: isComplete, Callback: NonsignificanTokenCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: isInsideGroup, Callback: UnclosedGroupCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: isIncompleteComparison, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserCloseGroup), Predicate: isInsideGroup, Callback: CloseGroupCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: isOutsideGroup, Callback: UnopenedGroupCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserOperand), Predicate: isInsideArgumentList, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: isOutsideArgumentList, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserOperand), Predicate: isComparable, Callback: ComparisonCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: isNotComparable, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserOperand), Predicate: isInsideArgumentList, Callback: SeparatingConjunctionCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserOperand), Predicate: isJoinable, Callback: ConjunctionCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: isNotJoinableOrSeparating, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserConversion), Predicate: isConvertible, Callback: ConversionCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: isNotConvertible, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserPostfix), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 
 	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserFinal), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserAssignmentTarget), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignment), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserQuestion), Predicate: nil, Callback: NonsignificanTokenCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserAssignmentTarget), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserConverted), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserConverted), Predicate: nil, Callback: SignificantTokenCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConversion), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserPunctuation), Next: sm.State(stateParserFinal), Predicate: nil, Callback: NonsignificanTokenCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserQuestion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserNumber), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserOperand), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserInvalid), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserOpenGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserCloseGroup), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserAssignment), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserBinding), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserIdentifier), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserSeparator), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserComparison), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserConjunction), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserUnit), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserConversion), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
+	{Current: sm.State(stateParserConverted), Event: sm.Event(eventParserNumeralSystem), Next: sm.State(stateParserSyntaxError), Predicate: nil, Callback: SyntaxErrorCallback},
 }
 
 type ParserContext struct {
 	InputTokens  []Token
 	OutputTokens []Token
+	Operators    *OperatorRegistry
+
+	GroupDepth int
+	// Arguments holds the group depth of every open function argument list,
+	// innermost last.
+	Arguments []int
+
+	// YesNo is set while reading a yes/no question, and Compared once the
+	// comparison it is reading has been given its comparison phrase.
+	YesNo    bool
+	Compared bool
 }
@@ -1,6 +1,7 @@
 package interp_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/VitoNaychev/eval-web-service/interp"
@@ -11,13 +12,13 @@ func TestParser(t *testing.T) {
 	cases := []struct {
 		Name           string
 		Input          []interp.Token
-		ExpectedOutput []interp.Token
+		ExpectedOutput interp.Node
 		ExpectedError  error
 	}{
 		{
 			Name: "error on missing question token",
 			Input: []interp.Token{
-				&interp.NumberToken{"10"},
+				&interp.NumberToken{Value: "10"},
 			},
 			ExpectedOutput: nil,
 			ExpectedError:  interp.ErrInvalidSyntax,
@@ -25,8 +26,8 @@ func TestParser(t *testing.T) {
 		{
 			Name: "error on missing punctuation token",
 			Input: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"10"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
 			},
 			ExpectedOutput: nil,
 			ExpectedError:  interp.ErrInvalidSyntax,
@@ -34,22 +35,22 @@ func TestParser(t *testing.T) {
 		{
 			Name: "removes nonsignificant tokens (one number expression)",
 			Input: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"10"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
+				&interp.PunctuationToken{Value: "?"},
 			},
-			ExpectedOutput: []interp.Token{
-				&interp.NumberToken{"10"},
+			ExpectedOutput: &interp.NumberNode{
+				Number: &interp.NumberToken{Value: "10"},
 			},
 			ExpectedError: nil,
 		},
 		{
 			Name: "error on statement ending on operand",
 			Input: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"10"},
-				&interp.OperandToken{"plus"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedOutput: nil,
 			ExpectedError:  interp.ErrInvalidSyntax,
@@ -57,28 +58,28 @@ func TestParser(t *testing.T) {
 		{
 			Name: "removes nonsignificant tokens (number, operand, number expression)",
 			Input: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"10"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"20"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "20"},
+				&interp.PunctuationToken{Value: "?"},
 			},
-			ExpectedOutput: []interp.Token{
-				&interp.NumberToken{"10"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"20"},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "10"}},
+				Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "20"}},
 			},
 			ExpectedError: nil,
 		},
 		{
 			Name: "error on statement with two questions",
 			Input: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"10"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"20"},
-				&interp.QuestionToken{"What is"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "20"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedOutput: nil,
 			ExpectedError:  interp.ErrInvalidSyntax,
@@ -86,12 +87,12 @@ func TestParser(t *testing.T) {
 		{
 			Name: "error on statement with two punctuation marks",
 			Input: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"10"},
-				&interp.PunctuationToken{"?"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"20"},
-				&interp.PunctuationToken{"?"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
+				&interp.PunctuationToken{Value: "?"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "20"},
+				&interp.PunctuationToken{Value: "?"},
 			},
 			ExpectedOutput: nil,
 			ExpectedError:  interp.ErrInvalidSyntax,
@@ -99,13 +100,13 @@ func TestParser(t *testing.T) {
 		{
 			Name: "error on statement with operand after punctuation token",
 			Input: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"10"},
-				&interp.PunctuationToken{"?"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"20"},
-				&interp.PunctuationToken{"?"},
-				&interp.OperandToken{"multiplied by"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
+				&interp.PunctuationToken{Value: "?"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "20"},
+				&interp.PunctuationToken{Value: "?"},
+				&interp.OperandToken{Value: "multiplied by"},
 			},
 			ExpectedOutput: nil,
 			ExpectedError:  interp.ErrInvalidSyntax,
@@ -113,25 +114,894 @@ func TestParser(t *testing.T) {
 		{
 			Name: "error on statement with number after punctuation token",
 			Input: []interp.Token{
-				&interp.QuestionToken{"What is"},
-				&interp.NumberToken{"10"},
-				&interp.PunctuationToken{"?"},
-				&interp.OperandToken{"plus"},
-				&interp.NumberToken{"20"},
-				&interp.PunctuationToken{"?"},
-				&interp.NumberToken{"42"},
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
+				&interp.PunctuationToken{Value: "?"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "20"},
+				&interp.PunctuationToken{Value: "?"},
+				&interp.NumberToken{Value: "42"},
 			},
 			ExpectedOutput: nil,
 			ExpectedError:  interp.ErrInvalidSyntax,
 		},
+		{
+			Name: "binds multiplication tighter than addition",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "2"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "multiplied by"},
+				&interp.NumberToken{Value: "4"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+				Right: &interp.BinaryNode{
+					Operand: &interp.OperandToken{Value: "multiplied by"},
+					Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+					Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "4"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "associates operations of equal precedence to the left",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "10"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.NumberToken{Value: "2"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "minus"},
+				Left: &interp.BinaryNode{
+					Operand: &interp.OperandToken{Value: "minus"},
+					Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "10"}},
+					Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+				},
+				Right: &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "grouped subexpression overrides precedence",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.NumberToken{Value: "2"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.CloseGroupToken{Value: ")"},
+				&interp.OperandToken{Value: "multiplied by"},
+				&interp.NumberToken{Value: "4"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "multiplied by"},
+				Left: &interp.BinaryNode{
+					Operand: &interp.OperandToken{Value: "plus"},
+					Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+					Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+				},
+				Right: &interp.NumberNode{Number: &interp.NumberToken{Value: "4"}},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "nested groups",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "2"},
+				&interp.OperandToken{Value: "multiplied by"},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.NumberToken{Value: "3"},
+				&interp.CloseGroupToken{Value: ")"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.NumberToken{Value: "1"},
+				&interp.CloseGroupToken{Value: ")"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "multiplied by"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+				Right: &interp.BinaryNode{
+					Operand: &interp.OperandToken{Value: "minus"},
+					Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+					Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "1"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "error on group closed before punctuation",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.NumberToken{Value: "2"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrUnclosedGroup,
+		},
+		{
+			Name: "error on unclosed group at end of input",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.NumberToken{Value: "2"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrUnclosedGroup,
+		},
+		{
+			Name: "error on closing an unopened group",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "2"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.CloseGroupToken{Value: ")"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrUnopenedGroup,
+		},
+		{
+			Name: "error on empty group",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.CloseGroupToken{Value: ")"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrEmptyGroup,
+		},
+		{
+			Name: "leading minus negates the following number",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "5"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left: &interp.UnaryNode{
+					Operand: &interp.OperandToken{Value: "minus"},
+					Child:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+				},
+				Right: &interp.NumberNode{Number: &interp.NumberToken{Value: "5"}},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "minus after an operand is unary",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "5"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "minus"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "5"}},
+				Right: &interp.UnaryNode{
+					Operand: &interp.OperandToken{Value: "minus"},
+					Child:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "minus negates a group",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.NumberToken{Value: "2"},
+				&interp.CloseGroupToken{Value: ")"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.UnaryNode{
+				Operand: &interp.OperandToken{Value: "minus"},
+				Child:   &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "error on leading operand other than minus",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrInvalidSyntax,
+		},
+		{
+			Name: "error on negation without a number",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.OperandToken{Value: "minus"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrInvalidSyntax,
+		},
+		{
+			Name: "associates powers to the right",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "2"},
+				&interp.OperandToken{Value: "raised to the power of"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "raised to the power of"},
+				&interp.NumberToken{Value: "2"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "raised to the power of"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+				Right: &interp.BinaryNode{
+					Operand: &interp.OperandToken{Value: "raised to the power of"},
+					Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+					Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "binds modulo like multiplication",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "1"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "7"},
+				&interp.OperandToken{Value: "modulo"},
+				&interp.NumberToken{Value: "3"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "1"}},
+				Right: &interp.BinaryNode{
+					Operand: &interp.OperandToken{Value: "modulo"},
+					Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "7"}},
+					Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "postfix operand applies to the preceding number",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "2"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "3"},
+				&interp.OperandToken{Value: "squared"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+				Right: &interp.UnaryNode{
+					Operand: &interp.OperandToken{Value: "squared"},
+					Child:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "postfix operand applies to a group",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OpenGroupToken{Value: "("},
+				&interp.NumberToken{Value: "1"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "2"},
+				&interp.CloseGroupToken{Value: ")"},
+				&interp.OperandToken{Value: "cubed"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.UnaryNode{
+				Operand: &interp.OperandToken{Value: "cubed"},
+				Child: &interp.BinaryNode{
+					Operand: &interp.OperandToken{Value: "plus"},
+					Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "1"}},
+					Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "square root applies to the following term",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OperandToken{Value: "the square root of"},
+				&interp.NumberToken{Value: "16"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.NumberToken{Value: "1"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left: &interp.UnaryNode{
+					Operand: &interp.OperandToken{Value: "the square root of"},
+					Child:   &interp.NumberNode{Number: &interp.NumberToken{Value: "16"}},
+				},
+				Right: &interp.NumberNode{Number: &interp.NumberToken{Value: "1"}},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "error on postfix operand without a number",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.OperandToken{Value: "squared"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrInvalidSyntax,
+		},
+		{
+			Name: "assignment",
+			Input: []interp.Token{
+				&interp.AssignmentToken{Value: "let"},
+				&interp.IdentifierToken{Value: "x"},
+				&interp.BindingToken{Value: "be"},
+				&interp.NumberToken{Value: "5"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.IdentifierToken{Value: "y"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.AssignmentNode{
+				Identifier: &interp.IdentifierToken{Value: "x"},
+				Value: &interp.BinaryNode{
+					Operand: &interp.OperandToken{Value: "plus"},
+					Left: &interp.NumberNode{
+						Number: &interp.NumberToken{Value: "5"},
+					},
+					Right: &interp.VariableNode{
+						Identifier: &interp.IdentifierToken{Value: "y"},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "previous result",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.PreviousResultToken{Value: "the result"},
+				&interp.OperandToken{Value: "squared"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: &interp.UnaryNode{
+				Operand: &interp.OperandToken{Value: "squared"},
+				Child: &interp.PreviousResultNode{
+					Reference: &interp.PreviousResultToken{Value: "the result"},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "error on assigning to the previous result",
+			Input: []interp.Token{
+				&interp.AssignmentToken{Value: "let"},
+				&interp.PreviousResultToken{Value: "the result"},
+				&interp.BindingToken{Value: "be"},
+				&interp.NumberToken{Value: "5"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrInvalidSyntax,
+		},
+		{
+			Name: "error on assignment without a variable",
+			Input: []interp.Token{
+				&interp.AssignmentToken{Value: "let"},
+				&interp.NumberToken{Value: "5"},
+				&interp.BindingToken{Value: "be"},
+				&interp.NumberToken{Value: "5"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrInvalidSyntax,
+		},
+		{
+			Name: "error on assignment without a binding",
+			Input: []interp.Token{
+				&interp.AssignmentToken{Value: "let"},
+				&interp.IdentifierToken{Value: "x"},
+				&interp.NumberToken{Value: "5"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrInvalidSyntax,
+		},
+		{
+			Name: "error on assignment inside a question",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "5"},
+				&interp.OperandToken{Value: "plus"},
+				&interp.AssignmentToken{Value: "let"},
+				&interp.IdentifierToken{Value: "x"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrInvalidSyntax,
+		},
+		{
+			Name: "error on square root after a number",
+			Input: []interp.Token{
+				&interp.QuestionToken{Value: "What is"},
+				&interp.NumberToken{Value: "4"},
+				&interp.OperandToken{Value: "the square root of"},
+				&interp.NumberToken{Value: "16"},
+				&interp.PunctuationToken{Value: "?"},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  interp.ErrInvalidSyntax,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			gotTree, gotError := interp.Parse(test.Input)
+
+			assert.Equal(t, gotTree, test.ExpectedOutput)
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+		})
+	}
+}
+
+func TestParserLeftToRight(t *testing.T) {
+	t.Run("folds operations left to right regardless of precedence", func(t *testing.T) {
+		input := []interp.Token{
+			&interp.QuestionToken{Value: "What is"},
+			&interp.NumberToken{Value: "2"},
+			&interp.OperandToken{Value: "plus"},
+			&interp.NumberToken{Value: "3"},
+			&interp.OperandToken{Value: "multiplied by"},
+			&interp.NumberToken{Value: "4"},
+			&interp.PunctuationToken{Value: "?"},
+		}
+		wantTree := &interp.BinaryNode{
+			Operand: &interp.OperandToken{Value: "multiplied by"},
+			Left: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+				Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+			},
+			Right: &interp.NumberNode{Number: &interp.NumberToken{Value: "4"}},
+		}
+
+		gotTree, err := interp.ParseLeftToRight(input)
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotTree, wantTree)
+	})
+
+	t.Run("error on invalid syntax", func(t *testing.T) {
+		input := []interp.Token{
+			&interp.QuestionToken{Value: "What is"},
+			&interp.OperandToken{Value: "plus"},
+			&interp.PunctuationToken{Value: "?"},
+		}
+
+		_, gotError := interp.ParseLeftToRight(input)
+
+		assert.ErrorIs(t, gotError, interp.ErrInvalidSyntax)
+	})
+}
+
+func TestParserDiagnostics(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Input         string
+		ExpectedError error
+		Expected      *interp.Diagnostic
+	}{
+		{
+			Name:          "unexpected operand",
+			Input:         "What is 5 plus plus 3?",
+			ExpectedError: interp.ErrInvalidSyntax,
+			Expected: &interp.Diagnostic{
+				Position: interp.Position{Offset: 15, Length: 4},
+				Text:     "plus",
+				Expected: []string{"number", "identifier", "prefix operand", "function", "open group"},
+			},
+		},
+		{
+			Name:          "unexpected number",
+			Input:         "What is 5 twenty?",
+			ExpectedError: interp.ErrInvalidSyntax,
+			Expected: &interp.Diagnostic{
+				Position: interp.Position{Offset: 10, Length: 6},
+				Text:     "20",
+				Expected: []string{"operand", "unit", "conversion", "punctuation"},
+			},
+		},
+		{
+			Name:          "unexpected end of input",
+			Input:         "What is 5 plus",
+			ExpectedError: interp.ErrInvalidSyntax,
+			Expected: &interp.Diagnostic{
+				Position: interp.Position{Offset: 14, Length: 0},
+				Text:     "",
+				Expected: []string{"number", "identifier", "prefix operand", "function", "open group"},
+			},
+		},
+		{
+			Name:          "unclosed group",
+			Input:         "What is (5 plus 3?",
+			ExpectedError: interp.ErrUnclosedGroup,
+			Expected: &interp.Diagnostic{
+				Position: interp.Position{Offset: 17, Length: 1},
+				Text:     "?",
+				Expected: []string{"operand", "unit", "close group"},
+			},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			_, gotError := interp.Parse(tokens)
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+
+			var diagnostic *interp.Diagnostic
+			assert.Equal(t, errors.As(gotError, &diagnostic), true)
+
+			assert.Equal(t, diagnostic.Position, test.Expected.Position)
+			assert.Equal(t, diagnostic.Text, test.Expected.Text)
+			assert.Equal(t, diagnostic.Expected, test.Expected.Expected)
+		})
+	}
+
+	t.Run("describes the error in its message", func(t *testing.T) {
+		tokens, err := interp.Lex("What is 5 plus plus 3?")
+		assert.RequireNoError(t, err)
+
+		_, gotError := interp.Parse(tokens)
+		assert.RequireNotNil(t, gotError)
+
+		wantMessage := `invalid syntax at "plus" (offset 15), expected number, identifier, prefix operand, function or open group`
+		assert.Equal(t, gotError.Error(), wantMessage)
+	})
+}
+
+func TestParserFunctions(t *testing.T) {
+	t.Run("reads the arguments of a function", func(t *testing.T) {
+		tokens, err := interp.Lex("What is the sum of 1, 2 and 3 multiplied by 4?")
+		assert.RequireNoError(t, err)
+
+		wantTree := &interp.BinaryNode{
+			Operand: &interp.OperandToken{Value: "multiplied by"},
+			Left: &interp.FunctionNode{
+				Function: &interp.OperandToken{Value: "the sum of"},
+				Arguments: []interp.Node{
+					&interp.NumberNode{Number: &interp.NumberToken{Value: "1"}},
+					&interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+					&interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+				},
+			},
+			Right: &interp.NumberNode{Number: &interp.NumberToken{Value: "4"}},
+		}
+
+		gotTree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotTree, wantTree)
+	})
+
+	t.Run("binds separators to the innermost function", func(t *testing.T) {
+		tokens, err := interp.Lex("What is the maximum of 1 and the minimum of 2 and 3?")
+		assert.RequireNoError(t, err)
+
+		wantTree := &interp.FunctionNode{
+			Function: &interp.OperandToken{Value: "the maximum of"},
+			Arguments: []interp.Node{
+				&interp.NumberNode{Number: &interp.NumberToken{Value: "1"}},
+				&interp.FunctionNode{
+					Function: &interp.OperandToken{Value: "the minimum of"},
+					Arguments: []interp.Node{
+						&interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+						&interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+					},
+				},
+			},
+		}
+
+		gotTree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotTree, wantTree)
+	})
+
+	t.Run("reads groups as arguments", func(t *testing.T) {
+		tokens, err := interp.Lex("What is the product of (1 plus 2), 3?")
+		assert.RequireNoError(t, err)
+
+		_, err = interp.Parse(tokens)
+		assert.RequireNoError(t, err)
+	})
+
+	cases := []struct {
+		Name          string
+		Input         string
+		ExpectedError error
+	}{
+		{"separator outside a function", "What is 1, 2?", interp.ErrInvalidSyntax},
+		{"separator after an infix operand", "What is the sum of 1 plus 2, 3?", interp.ErrInvalidSyntax},
+		{"separator inside a group of the arguments", "What is the sum of (1, 2)?", interp.ErrInvalidSyntax},
+		{"function without arguments", "What is the sum of?", interp.ErrInvalidSyntax},
+		{"trailing separator", "What is the sum of 1 and?", interp.ErrInvalidSyntax},
 	}
 
 	for _, test := range cases {
 		t.Run(test.Name, func(t *testing.T) {
-			gotTokens, gotError := interp.Parse(test.Input)
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
 
-			assert.Equal(t, gotTokens, test.ExpectedOutput)
-			assert.Equal(t, gotError, test.ExpectedError)
+			_, gotError := interp.Parse(tokens)
+
+			assert.ErrorIs(t, gotError, test.ExpectedError)
 		})
 	}
 }
+
+func TestParserYesNoQuestions(t *testing.T) {
+	t.Run("compares two expressions", func(t *testing.T) {
+		tokens, err := interp.Lex("Is 2 plus 3 at least 4?")
+		assert.RequireNoError(t, err)
+
+		wantTree := &interp.ComparisonNode{
+			Comparison: &interp.ComparisonToken{Value: "at least"},
+			Left: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "2"}},
+				Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+			},
+			Right: &interp.NumberNode{Number: &interp.NumberToken{Value: "4"}},
+		}
+
+		gotTree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotTree, wantTree)
+	})
+
+	t.Run("binds and tighter than or", func(t *testing.T) {
+		tokens, err := interp.Lex("Is 1 equal to 2 or 3 less than 4 and 5 greater than 6?")
+		assert.RequireNoError(t, err)
+
+		comparison := func(phrase, left, right string) interp.Node {
+			return &interp.ComparisonNode{
+				Comparison: &interp.ComparisonToken{Value: phrase},
+				Left:       &interp.NumberNode{Number: &interp.NumberToken{Value: left}},
+				Right:      &interp.NumberNode{Number: &interp.NumberToken{Value: right}},
+			}
+		}
+		wantTree := &interp.ConjunctionNode{
+			Conjunction: &interp.ConjunctionToken{Value: "or"},
+			Left:        comparison("equal to", "1", "2"),
+			Right: &interp.ConjunctionNode{
+				Conjunction: &interp.ConjunctionToken{Value: "and"},
+				Left:        comparison("less than", "3", "4"),
+				Right:       comparison("greater than", "5", "6"),
+			},
+		}
+
+		gotTree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotTree, wantTree)
+	})
+
+	t.Run("separates the arguments of a function with and", func(t *testing.T) {
+		tokens, err := interp.Lex("Is the sum of 1 and 2 greater than 2 and 1 less than 2?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		conjunction, ok := tree.(*interp.ConjunctionNode)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, conjunction.Conjunction.Value, "and")
+	})
+
+	cases := []struct {
+		Name          string
+		Input         string
+		ExpectedError error
+	}{
+		{"yes/no question without comparison", "Is 5?", interp.ErrInvalidSyntax},
+		{"comparison in a math question", "What is 5 greater than 3?", interp.ErrInvalidSyntax},
+		{"two comparisons without a conjunction", "Is 1 less than 2 less than 3?", interp.ErrInvalidSyntax},
+		{"comparison inside a group", "Is (1 less than 2)?", interp.ErrInvalidSyntax},
+		{"conjunction before the comparison", "Is 1 and 2 less than 3?", interp.ErrInvalidSyntax},
+		{"conjunction in a math question", "What is 1 or 2?", interp.ErrInvalidSyntax},
+		{"trailing conjunction", "Is 1 less than 2 or?", interp.ErrInvalidSyntax},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			_, gotError := interp.Parse(tokens)
+
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+		})
+	}
+
+	t.Run("expects a comparison", func(t *testing.T) {
+		tokens, err := interp.Lex("Is 5?")
+		assert.RequireNoError(t, err)
+
+		_, gotError := interp.Parse(tokens)
+
+		var diagnostic *interp.Diagnostic
+		assert.Equal(t, errors.As(gotError, &diagnostic), true)
+		assert.Equal(t, diagnostic.Expected, []string{"operand", "unit", "comparison"})
+	})
+}
+
+func TestParserUnits(t *testing.T) {
+	t.Run("measures terms and converts the statement", func(t *testing.T) {
+		tokens, err := interp.Lex("What is 5 kilometers plus 300 meters in meters?")
+		assert.RequireNoError(t, err)
+
+		wantTree := &interp.ConversionNode{
+			Conversion: &interp.ConversionToken{Value: "in"},
+			Unit:       &interp.UnitToken{Value: "meters"},
+			Expression: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "plus"},
+				Left: &interp.QuantityNode{
+					Unit:  &interp.UnitToken{Value: "kilometers"},
+					Child: &interp.NumberNode{Number: &interp.NumberToken{Value: "5"}},
+				},
+				Right: &interp.QuantityNode{
+					Unit:  &interp.UnitToken{Value: "meters"},
+					Child: &interp.NumberNode{Number: &interp.NumberToken{Value: "300"}},
+				},
+			},
+		}
+
+		gotTree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotTree, wantTree)
+	})
+
+	t.Run("measures groups", func(t *testing.T) {
+		tokens, err := interp.Lex("What is (1 plus 2) hours?")
+		assert.RequireNoError(t, err)
+
+		tree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		quantity, ok := tree.(*interp.QuantityNode)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, quantity.Unit.Value, "hours")
+	})
+
+	cases := []struct {
+		Name          string
+		Input         string
+		ExpectedError error
+	}{
+		{"unit without a term", "What is meters?", interp.ErrInvalidSyntax},
+		{"two units", "What is 5 meters seconds?", interp.ErrInvalidSyntax},
+		{"conversion without a unit", "What is 5 meters in?", interp.ErrInvalidSyntax},
+		{"conversion inside a group", "What is (5 meters in meters)?", interp.ErrInvalidSyntax},
+		{"conversion of a yes/no question", "Is 5 meters less than 6 meters in meters?", interp.ErrInvalidSyntax},
+		{"operation after a conversion", "What is 5 meters in meters plus 1?", interp.ErrInvalidSyntax},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			_, gotError := interp.Parse(tokens)
+
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+		})
+	}
+}
+
+func TestParserNumeralSystems(t *testing.T) {
+	t.Run("converts the statement to a numeral system", func(t *testing.T) {
+		tokens, err := interp.Lex("What is XIV multiplied by III in Roman numerals?")
+		assert.RequireNoError(t, err)
+
+		wantTree := &interp.NumeralSystemNode{
+			Conversion:    &interp.ConversionToken{Value: "in"},
+			NumeralSystem: &interp.NumeralSystemToken{Value: "roman numerals"},
+			Expression: &interp.BinaryNode{
+				Operand: &interp.OperandToken{Value: "multiplied by"},
+				Left:    &interp.NumberNode{Number: &interp.NumberToken{Value: "14"}},
+				Right:   &interp.NumberNode{Number: &interp.NumberToken{Value: "3"}},
+			},
+		}
+
+		gotTree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotTree, wantTree)
+	})
+
+	cases := []struct {
+		Name          string
+		Input         string
+		ExpectedError error
+	}{
+		{"numeral system without a conversion", "What is 5 hexadecimal?", interp.ErrInvalidSyntax},
+		{"conversion inside a group", "What is (5 in hexadecimal)?", interp.ErrInvalidSyntax},
+		{"operation after a conversion", "What is 5 in hexadecimal plus 1?", interp.ErrInvalidSyntax},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := interp.Lex(test.Input)
+			assert.RequireNoError(t, err)
+
+			_, gotError := interp.Parse(tokens)
+
+			assert.ErrorIs(t, gotError, test.ExpectedError)
+		})
+	}
+}
+
+func TestParserDates(t *testing.T) {
+	t.Run("reads date literals as terms", func(t *testing.T) {
+		tokens, err := interp.Lex("What is 2024-03-01T10:00 plus 45 days?")
+		assert.RequireNoError(t, err)
+
+		wantTree := &interp.BinaryNode{
+			Operand: &interp.OperandToken{Value: "plus"},
+			Left:    &interp.DateNode{Date: &interp.DateToken{Value: "2024-03-01t10:00"}},
+			Right: &interp.QuantityNode{
+				Unit:  &interp.UnitToken{Value: "days"},
+				Child: &interp.NumberNode{Number: &interp.NumberToken{Value: "45"}},
+			},
+		}
+
+		gotTree, err := interp.Parse(withoutPositions(tokens))
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotTree, wantTree)
+	})
+
+	t.Run("error on two dates in a row", func(t *testing.T) {
+		tokens, err := interp.Lex("What is 2024-03-01 2024-01-01?")
+		assert.RequireNoError(t, err)
+
+		_, gotError := interp.Parse(tokens)
+
+		assert.ErrorIs(t, gotError, interp.ErrInvalidSyntax)
+	})
+}
@@ -2,6 +2,7 @@ package interp
 
 type ParserError struct {
 	msg string
+	err error
 }
 
 func NewParserError(msg string) *ParserError {
@@ -10,10 +11,136 @@ func NewParserError(msg string) *ParserError {
 	}
 }
 
+// NewSyntaxError returns a ParserError that describes a specific syntax
+// problem while still matching ErrInvalidSyntax through errors.Is.
+func NewSyntaxError(msg string) *ParserError {
+	return &ParserError{
+		msg: ErrInvalidSyntax.msg + ": " + msg,
+		err: ErrInvalidSyntax,
+	}
+}
+
 func (p *ParserError) Error() string {
 	return p.msg
 }
 
+func (p *ParserError) Unwrap() error {
+	return p.err
+}
+
 var (
 	ErrInvalidSyntax = NewParserError("invalid syntax")
+
+	ErrUnclosedGroup = NewSyntaxError("unclosed group")
+	ErrUnopenedGroup = NewSyntaxError("closing an unopened group")
+	ErrEmptyGroup    = NewSyntaxError("empty group")
 )
+
+type Node interface {
+	isNode()
+}
+
+type NumberNode struct {
+	Number *NumberToken
+}
+
+func (n *NumberNode) isNode() {}
+
+// DateNode is a date literal.
+type DateNode struct {
+	Date *DateToken
+}
+
+func (d *DateNode) isNode() {}
+
+type BinaryNode struct {
+	Operand *OperandToken
+	Left    Node
+	Right   Node
+}
+
+func (b *BinaryNode) isNode() {}
+
+type UnaryNode struct {
+	Operand *OperandToken
+	Child   Node
+}
+
+func (u *UnaryNode) isNode() {}
+
+// FunctionNode applies a function to a list of arguments.
+type FunctionNode struct {
+	Function  *OperandToken
+	Arguments []Node
+}
+
+func (f *FunctionNode) isNode() {}
+
+// QuantityNode measures the value of its child in a unit.
+type QuantityNode struct {
+	Unit  *UnitToken
+	Child Node
+}
+
+func (q *QuantityNode) isNode() {}
+
+// ConversionNode converts the value of an expression to another unit of the
+// same dimension.
+type ConversionNode struct {
+	Conversion *ConversionToken
+	Unit       *UnitToken
+	Expression Node
+}
+
+func (c *ConversionNode) isNode() {}
+
+// NumeralSystemNode writes the value of an expression in a numeral system,
+// e.g. in hexadecimal.
+type NumeralSystemNode struct {
+	Conversion    *ConversionToken
+	NumeralSystem *NumeralSystemToken
+	Expression    Node
+}
+
+func (n *NumeralSystemNode) isNode() {}
+
+// ComparisonNode compares the values of two expressions.
+type ComparisonNode struct {
+	Comparison *ComparisonToken
+	Left       Node
+	Right      Node
+}
+
+func (c *ComparisonNode) isNode() {}
+
+// ConjunctionNode joins two comparisons, or other conjunctions, with "and"
+// or "or".
+type ConjunctionNode struct {
+	Conjunction *ConjunctionToken
+	Left        Node
+	Right       Node
+}
+
+func (c *ConjunctionNode) isNode() {}
+
+// VariableNode reads the value bound to a variable.
+type VariableNode struct {
+	Identifier *IdentifierToken
+}
+
+func (v *VariableNode) isNode() {}
+
+// PreviousResultNode reads the result of the previous evaluation.
+type PreviousResultNode struct {
+	Reference *PreviousResultToken
+}
+
+func (p *PreviousResultNode) isNode() {}
+
+// AssignmentNode binds the value of an expression to a variable.
+type AssignmentNode struct {
+	Identifier *IdentifierToken
+	Value      Node
+}
+
+func (a *AssignmentNode) isNode() {}
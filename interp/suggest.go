@@ -0,0 +1,115 @@
+package interp
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultSuggestionDistance is the largest edit distance at which a new
+// OperatorRegistry suggests a known word in place of one it can't read.
+const DefaultSuggestionDistance = 2
+
+// suggester suggests the known words closest to a word the lexer can't read,
+// e.g. "plus" for "plsu". Its vocabulary holds every word of the phrases
+// and the number words of a registry and its locale.
+type suggester struct {
+	vocabulary  map[string]bool
+	maxDistance int
+}
+
+func newSuggester(operators *OperatorRegistry) *suggester {
+	s := &suggester{
+		vocabulary:  map[string]bool{},
+		maxDistance: operators.suggestionDistance,
+	}
+
+	locale := operators.locale
+	s.add(locale.Questions...)
+	s.add(locale.YesNoQuestions...)
+	s.add(locale.Assignments...)
+	s.add(locale.Bindings...)
+	s.add(locale.PreviousResults...)
+	s.add(locale.comparisonPhrases()...)
+	s.add(locale.conjunctionPhrases()...)
+	s.add(locale.unitPhrases()...)
+	s.add(locale.numeralSystemPhrases()...)
+	s.add(locale.Conversions...)
+	for _, operator := range operators.operators {
+		s.add(operator.Phrase)
+	}
+
+	words := locale.NumberWords
+	s.add(words.Zero, words.Hundred)
+	for _, group := range []map[string]int64{words.Ones, words.Teens, words.Tens, words.Hundreds, words.Scales} {
+		for word := range group {
+			s.add(word)
+		}
+	}
+
+	return s
+}
+
+func (s *suggester) add(phrases ...string) {
+	for _, phrase := range phrases {
+		for _, word := range strings.Fields(phrase) {
+			s.vocabulary[word] = true
+		}
+	}
+}
+
+// suggest returns the known words closest to a word, in alphabetical order.
+// Words farther than the maximum distance aren't suggested, and neither are
+// those that would replace every letter of the word.
+func (s *suggester) suggest(word string) []string {
+	length := len([]rune(word))
+
+	best := s.maxDistance
+	suggestions := []string{}
+	for known := range s.vocabulary {
+		distance := editDistance(word, known)
+		if distance == 0 || distance > best || distance >= length {
+			continue
+		}
+
+		if distance < best {
+			best = distance
+			suggestions = suggestions[:0]
+		}
+		suggestions = append(suggestions, known)
+	}
+
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// editDistance counts the insertions, deletions and substitutions of letters
+// and the transpositions of adjacent letters that turn a into b, so "plsu"
+// is one edit away from "plus".
+func editDistance(a, b string) int {
+	x, y := []rune(a), []rune(b)
+
+	d := make([][]int, len(x)+1)
+	for i := range d {
+		d[i] = make([]int, len(y)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && x[i-1] == y[j-2] && x[i-2] == y[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(x)][len(y)]
+}
@@ -2,19 +2,28 @@ package interp
 
 type Token interface {
 	GetToken() interface{}
+	GetPosition() Position
+	SetPosition(Position)
 }
 
-type NewTokenFunc func(string) Token
+type NewTokenFunc func(string, Position) Token
 
-var QuestionTokenPattern = `^What is`
+// Position locates a token in the original input as a byte offset and the
+// byte length of the text it was read from.
+type Position struct {
+	Offset int
+	Length int
+}
 
 type QuestionToken struct {
-	Value string
+	Value    string
+	Position Position
 }
 
-func NewQuestionToken(value string) Token {
+func NewQuestionToken(value string, pos Position) Token {
 	return &QuestionToken{
-		Value: value,
+		Value:    value,
+		Position: pos,
 	}
 }
 
@@ -22,15 +31,28 @@ func (q *QuestionToken) GetToken() interface{} {
 	return q.Value
 }
 
-var NumberTokenPattern = `^\d+`
+func (q *QuestionToken) GetPosition() Position {
+	return q.Position
+}
+
+func (q *QuestionToken) SetPosition(pos Position) {
+	q.Position = pos
+}
+
+// NumberTokenPattern matches whole and decimal literals, e.g. "42" or
+// "-0.5". A decimal point is always followed by a digit, so it is never
+// confused with the full stop that ends a sentence.
+var NumberTokenPattern = `^-?\d+(\.\d+)?`
 
 type NumberToken struct {
-	Value string
+	Value    string
+	Position Position
 }
 
-func NewNumberToken(value string) Token {
+func NewNumberToken(value string, pos Position) Token {
 	return &NumberToken{
-		Value: value,
+		Value:    value,
+		Position: pos,
 	}
 }
 
@@ -38,15 +60,29 @@ func (n *NumberToken) GetToken() interface{} {
 	return n.Value
 }
 
-var OperandTokenPattern = `^(plus|minus|multiplied by|divided by)`
+func (n *NumberToken) GetPosition() Position {
+	return n.Position
+}
+
+func (n *NumberToken) SetPosition(pos Position) {
+	n.Position = pos
+}
+
+// NewNumberWordToken converts a spelled-out English number, e.g.
+// "twenty-three", to a NumberToken holding its digits.
+func NewNumberWordToken(value string, pos Position) Token {
+	return numberWordFinder{vocabulary: &English.NumberWords}.newToken(value, pos)
+}
 
 type OperandToken struct {
-	Value string
+	Value    string
+	Position Position
 }
 
-func NewOperandToken(value string) Token {
+func NewOperandToken(value string, pos Position) Token {
 	return &OperandToken{
-		Value: value,
+		Value:    value,
+		Position: pos,
 	}
 }
 
@@ -54,18 +90,402 @@ func (o *OperandToken) GetToken() interface{} {
 	return o.Value
 }
 
-var PunctuationTokenPattern = `^\?`
+func (o *OperandToken) GetPosition() Position {
+	return o.Position
+}
+
+func (o *OperandToken) SetPosition(pos Position) {
+	o.Position = pos
+}
 
 type PunctuationToken struct {
-	Value string
+	Value    string
+	Position Position
 }
 
-func NewPunctuationToken(value string) Token {
+func NewPunctuationToken(value string, pos Position) Token {
 	return &PunctuationToken{
-		Value: value,
+		Value:    value,
+		Position: pos,
 	}
 }
 
 func (p *PunctuationToken) GetToken() interface{} {
 	return p.Value
 }
+
+func (p *PunctuationToken) GetPosition() Position {
+	return p.Position
+}
+
+func (p *PunctuationToken) SetPosition(pos Position) {
+	p.Position = pos
+}
+
+var OpenGroupTokenPattern = `^\(`
+
+type OpenGroupToken struct {
+	Value    string
+	Position Position
+}
+
+func NewOpenGroupToken(value string, pos Position) Token {
+	return &OpenGroupToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (o *OpenGroupToken) GetToken() interface{} {
+	return o.Value
+}
+
+func (o *OpenGroupToken) GetPosition() Position {
+	return o.Position
+}
+
+func (o *OpenGroupToken) SetPosition(pos Position) {
+	o.Position = pos
+}
+
+var CloseGroupTokenPattern = `^\)`
+
+type CloseGroupToken struct {
+	Value    string
+	Position Position
+}
+
+func NewCloseGroupToken(value string, pos Position) Token {
+	return &CloseGroupToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (c *CloseGroupToken) GetToken() interface{} {
+	return c.Value
+}
+
+func (c *CloseGroupToken) GetPosition() Position {
+	return c.Position
+}
+
+func (c *CloseGroupToken) SetPosition(pos Position) {
+	c.Position = pos
+}
+
+// AssignmentToken opens a statement that binds a variable, e.g. the "let" in
+// "Let x be 5.".
+type AssignmentToken struct {
+	Value    string
+	Position Position
+}
+
+func NewAssignmentToken(value string, pos Position) Token {
+	return &AssignmentToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (a *AssignmentToken) GetToken() interface{} {
+	return a.Value
+}
+
+func (a *AssignmentToken) GetPosition() Position {
+	return a.Position
+}
+
+func (a *AssignmentToken) SetPosition(pos Position) {
+	a.Position = pos
+}
+
+// BindingToken separates the variable of an assignment from its value, e.g.
+// the "be" in "Let x be 5.".
+type BindingToken struct {
+	Value    string
+	Position Position
+}
+
+func NewBindingToken(value string, pos Position) Token {
+	return &BindingToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (b *BindingToken) GetToken() interface{} {
+	return b.Value
+}
+
+func (b *BindingToken) GetPosition() Position {
+	return b.Position
+}
+
+func (b *BindingToken) SetPosition(pos Position) {
+	b.Position = pos
+}
+
+// IdentifierToken names a variable. Identifiers are a single letter,
+// optionally followed by digits, e.g. "x" or "y2".
+type IdentifierToken struct {
+	Value    string
+	Position Position
+}
+
+func NewIdentifierToken(value string, pos Position) Token {
+	return &IdentifierToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (i *IdentifierToken) GetToken() interface{} {
+	return i.Value
+}
+
+func (i *IdentifierToken) GetPosition() Position {
+	return i.Position
+}
+
+func (i *IdentifierToken) SetPosition(pos Position) {
+	i.Position = pos
+}
+
+// PreviousResultToken refers to the result of the previous evaluation, e.g.
+// the "the result" in "What is the result multiplied by 2?".
+type PreviousResultToken struct {
+	Value    string
+	Position Position
+}
+
+func NewPreviousResultToken(value string, pos Position) Token {
+	return &PreviousResultToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (p *PreviousResultToken) GetToken() interface{} {
+	return p.Value
+}
+
+func (p *PreviousResultToken) GetPosition() Position {
+	return p.Position
+}
+
+func (p *PreviousResultToken) SetPosition(pos Position) {
+	p.Position = pos
+}
+
+// SeparatorToken separates the arguments of a function, e.g. the "," and the
+// "and" in "the sum of 1, 2 and 3".
+type SeparatorToken struct {
+	Value    string
+	Position Position
+}
+
+func NewSeparatorToken(value string, pos Position) Token {
+	return &SeparatorToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (s *SeparatorToken) GetToken() interface{} {
+	return s.Value
+}
+
+func (s *SeparatorToken) GetPosition() Position {
+	return s.Position
+}
+
+func (s *SeparatorToken) SetPosition(pos Position) {
+	s.Position = pos
+}
+
+// YesNoQuestionToken opens a question answered with yes or no, e.g. the "is"
+// in "Is 5 greater than 3?".
+type YesNoQuestionToken struct {
+	Value    string
+	Position Position
+}
+
+func NewYesNoQuestionToken(value string, pos Position) Token {
+	return &YesNoQuestionToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (y *YesNoQuestionToken) GetToken() interface{} {
+	return y.Value
+}
+
+func (y *YesNoQuestionToken) GetPosition() Position {
+	return y.Position
+}
+
+func (y *YesNoQuestionToken) SetPosition(pos Position) {
+	y.Position = pos
+}
+
+// ComparisonToken compares the expressions on either side of it, e.g. the
+// "greater than" in "Is 5 greater than 3?".
+type ComparisonToken struct {
+	Value    string
+	Position Position
+}
+
+func NewComparisonToken(value string, pos Position) Token {
+	return &ComparisonToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (c *ComparisonToken) GetToken() interface{} {
+	return c.Value
+}
+
+func (c *ComparisonToken) GetPosition() Position {
+	return c.Position
+}
+
+func (c *ComparisonToken) SetPosition(pos Position) {
+	c.Position = pos
+}
+
+// ConjunctionToken joins the comparisons of a yes/no question, e.g. the "or"
+// in "Is 5 less than 3 or 5 greater than 4?". Inside the arguments of a
+// function it separates them instead.
+type ConjunctionToken struct {
+	Value    string
+	Position Position
+}
+
+func NewConjunctionToken(value string, pos Position) Token {
+	return &ConjunctionToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (c *ConjunctionToken) GetToken() interface{} {
+	return c.Value
+}
+
+func (c *ConjunctionToken) GetPosition() Position {
+	return c.Position
+}
+
+func (c *ConjunctionToken) SetPosition(pos Position) {
+	c.Position = pos
+}
+
+// UnitToken names the unit of the term before it, e.g. the "kilometers" in
+// "What is 5 kilometers plus 300 meters?", or the unit a conversion converts
+// to.
+type UnitToken struct {
+	Value    string
+	Position Position
+}
+
+func NewUnitToken(value string, pos Position) Token {
+	return &UnitToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (u *UnitToken) GetToken() interface{} {
+	return u.Value
+}
+
+func (u *UnitToken) GetPosition() Position {
+	return u.Position
+}
+
+func (u *UnitToken) SetPosition(pos Position) {
+	u.Position = pos
+}
+
+// ConversionToken converts the result of a statement to the unit that
+// follows it, e.g. the "in" in "What is 5 kilometers in meters?".
+type ConversionToken struct {
+	Value    string
+	Position Position
+}
+
+func NewConversionToken(value string, pos Position) Token {
+	return &ConversionToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (c *ConversionToken) GetToken() interface{} {
+	return c.Value
+}
+
+func (c *ConversionToken) GetPosition() Position {
+	return c.Position
+}
+
+func (c *ConversionToken) SetPosition(pos Position) {
+	c.Position = pos
+}
+
+var DateTokenPattern = `^\d{4}-\d{2}-\d{2}(t\d{2}:\d{2}(:\d{2})?)?`
+
+// DateToken is a date, optionally with a time of day, e.g. the "2024-03-01"
+// in "What is 2024-03-01 plus 45 days?".
+type DateToken struct {
+	Value    string
+	Position Position
+}
+
+func NewDateToken(value string, pos Position) Token {
+	return &DateToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (d *DateToken) GetToken() interface{} {
+	return d.Value
+}
+
+func (d *DateToken) GetPosition() Position {
+	return d.Position
+}
+
+func (d *DateToken) SetPosition(pos Position) {
+	d.Position = pos
+}
+
+// NumeralSystemToken names the numeral system a conversion writes the result
+// of a statement in, e.g. the "hexadecimal" in "What is 255 in hexadecimal?".
+type NumeralSystemToken struct {
+	Value    string
+	Position Position
+}
+
+func NewNumeralSystemToken(value string, pos Position) Token {
+	return &NumeralSystemToken{
+		Value:    value,
+		Position: pos,
+	}
+}
+
+func (n *NumeralSystemToken) GetToken() interface{} {
+	return n.Value
+}
+
+func (n *NumeralSystemToken) GetPosition() Position {
+	return n.Position
+}
+
+func (n *NumeralSystemToken) SetPosition(pos Position) {
+	n.Position = pos
+}
@@ -0,0 +1,102 @@
+package interp
+
+import "regexp"
+
+type PatternFinder interface {
+	FindString(string) string
+}
+
+// TokenDefinition describes how a single kind of token is found in the input
+// and constructed from the matched text.
+type TokenDefinition struct {
+	Finder      PatternFinder
+	Constructor NewTokenFunc
+	Priority    int
+}
+
+// TokenRegistry holds the token definitions known to the lexer. Matching uses
+// maximal munch: the definition with the longest match wins, ties are broken
+// by the higher Priority and then by registration order, so the same input is
+// always split into the same tokens.
+type TokenRegistry struct {
+	definitions []TokenDefinition
+}
+
+func NewTokenRegistry() *TokenRegistry {
+	return &TokenRegistry{
+		definitions: []TokenDefinition{},
+	}
+}
+
+func (t *TokenRegistry) Register(finder PatternFinder, constructor NewTokenFunc, priority int) {
+	t.definitions = append(t.definitions, TokenDefinition{
+		Finder:      finder,
+		Constructor: constructor,
+		Priority:    priority,
+	})
+}
+
+// Match returns the constructor of the token found at the start of the input
+// along with the matched text, or false if no definition matches.
+func (t *TokenRegistry) Match(input string) (NewTokenFunc, string, bool) {
+	var best *TokenDefinition
+	bestValue := ""
+
+	for i := range t.definitions {
+		definition := &t.definitions[i]
+
+		value := definition.Finder.FindString(input)
+		if value == "" {
+			continue
+		}
+
+		if best == nil || len(value) > len(bestValue) ||
+			(len(value) == len(bestValue) && definition.Priority > best.Priority) {
+			best = definition
+			bestValue = value
+		}
+	}
+
+	if best == nil {
+		return nil, "", false
+	}
+	return best.Constructor, bestValue, true
+}
+
+const (
+	priorityDefault = iota
+	priorityOperand
+	priorityNumber
+	priorityQuestion
+)
+
+func newTokenRegistry(operators *OperatorRegistry) *TokenRegistry {
+	registry := NewTokenRegistry()
+
+	locale := operators.locale
+	numberWords := numberWordFinder{vocabulary: &locale.NumberWords}
+
+	registry.Register(phraseFinder(locale.Questions), NewQuestionToken, priorityQuestion)
+	registry.Register(phraseFinder(locale.YesNoQuestions), NewYesNoQuestionToken, priorityQuestion)
+	registry.Register(phraseFinder(locale.Assignments), NewAssignmentToken, priorityQuestion)
+	registry.Register(phraseFinder(locale.Bindings), NewBindingToken, priorityOperand)
+	registry.Register(regexp.MustCompile(NumberTokenPattern), NewNumberToken, priorityNumber)
+	registry.Register(regexp.MustCompile(DateTokenPattern), NewDateToken, priorityNumber)
+	registry.Register(radixFinder{}, NewNumeralToken, priorityNumber)
+	registry.Register(numberWords, numberWords.newToken, priorityNumber)
+	registry.Register(phraseFinder(locale.PreviousResults), NewPreviousResultToken, priorityNumber)
+	registry.Register(operators, NewOperandToken, priorityOperand)
+	registry.Register(locale.comparisonPhrases(), NewComparisonToken, priorityOperand)
+	registry.Register(locale.conjunctionPhrases(), NewConjunctionToken, priorityOperand)
+	registry.Register(locale.unitPhrases(), NewUnitToken, priorityOperand)
+	registry.Register(locale.numeralSystemPhrases(), NewNumeralSystemToken, priorityOperand)
+	registry.Register(phraseFinder(locale.Conversions), NewConversionToken, priorityOperand)
+	registry.Register(phraseFinder(locale.Punctuation), NewPunctuationToken, priorityDefault)
+	registry.Register(phraseFinder(locale.Separators), NewSeparatorToken, priorityDefault)
+	registry.Register(regexp.MustCompile(OpenGroupTokenPattern), NewOpenGroupToken, priorityDefault)
+	registry.Register(regexp.MustCompile(CloseGroupTokenPattern), NewCloseGroupToken, priorityDefault)
+	registry.Register(identifierFinder{}, NewIdentifierToken, priorityDefault)
+	registry.Register(romanFinder{}, NewNumeralToken, priorityDefault)
+
+	return registry
+}
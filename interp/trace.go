@@ -0,0 +1,71 @@
+package interp
+
+import "strings"
+
+// Step is one operation applied while evaluating a statement, e.g. "2 plus
+// 3 = 5". Operator is the phrase of the operation as it was read, and
+// Position locates the token it was read from. Operands and Result hold the
+// textual form of the values it was applied to and produced.
+type Step struct {
+	Operator string
+	Notation Notation
+	Operands []string
+	Result   string
+	Position Position
+}
+
+// String writes the step with its operator where it stands in the statement,
+// e.g. "2 plus 3 = 5", "3 squared = 9" or "the sum of 1, 2, 3 = 6".
+func (s Step) String() string {
+	var operation string
+	switch s.Notation {
+	case Infix:
+		operation = strings.Join(s.Operands, " "+s.Operator+" ")
+	case Postfix:
+		operation = strings.Join(s.Operands, " ") + " " + s.Operator
+	default:
+		operation = s.Operator + " " + strings.Join(s.Operands, ", ")
+	}
+	return operation + " = " + s.Result
+}
+
+// Trace collects the steps of an evaluation in the order they are applied.
+type Trace struct {
+	Steps []Step
+}
+
+// record adds a step for the operation read from token. It does nothing on
+// a nil Trace, so the interpreter can record its steps whether or not they
+// were asked for.
+func (t *Trace) record(token Token, notation Notation, operands []Value, result Value) {
+	t.add(token.GetToken().(string), notation, token.GetPosition(), operands, result)
+}
+
+// recordConversion adds a step for a conversion such as "in km" or "in
+// hexadecimal", which is read from the conversion word and its target.
+func (t *Trace) recordConversion(conversion, target Token, operand, result Value) {
+	start, end := conversion.GetPosition(), target.GetPosition()
+	position := Position{Offset: start.Offset, Length: end.Offset + end.Length - start.Offset}
+
+	operator := conversion.GetToken().(string) + " " + target.GetToken().(string)
+	t.add(operator, Postfix, position, []Value{operand}, result)
+}
+
+func (t *Trace) add(operator string, notation Notation, position Position, operands []Value, result Value) {
+	if t == nil {
+		return
+	}
+
+	step := Step{
+		Operator: operator,
+		Notation: notation,
+		Operands: make([]string, len(operands)),
+		Result:   result.String(),
+		Position: position,
+	}
+	for i, operand := range operands {
+		step.Operands[i] = operand.String()
+	}
+
+	t.Steps = append(t.Steps, step)
+}
@@ -0,0 +1,376 @@
+package interp
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Dimension is the kind of quantity a unit measures. Quantities can only be
+// added, subtracted and compared when they share a dimension.
+type Dimension int
+
+const (
+	Length Dimension = iota
+	Mass
+	Time
+	DataSize
+)
+
+// Unit is a unit of measurement. Factor is the size of the unit in the base
+// unit of its dimension: the meter, the gram, the second or the byte.
+// Quantities are rendered with the Symbol of their unit, e.g. "5300 m".
+type Unit struct {
+	Name      string
+	Symbol    string
+	Dimension Dimension
+	Factor    *big.Rat
+}
+
+// Units lists the units the language understands. The phrases for them are
+// supplied by the locale.
+var Units = []*Unit{
+	{Name: "millimeter", Symbol: "mm", Dimension: Length, Factor: big.NewRat(1, 1000)},
+	{Name: "centimeter", Symbol: "cm", Dimension: Length, Factor: big.NewRat(1, 100)},
+	{Name: "meter", Symbol: "m", Dimension: Length, Factor: big.NewRat(1, 1)},
+	{Name: "kilometer", Symbol: "km", Dimension: Length, Factor: big.NewRat(1000, 1)},
+	{Name: "inch", Symbol: "in", Dimension: Length, Factor: big.NewRat(254, 10000)},
+	{Name: "foot", Symbol: "ft", Dimension: Length, Factor: big.NewRat(3048, 10000)},
+	{Name: "yard", Symbol: "yd", Dimension: Length, Factor: big.NewRat(9144, 10000)},
+	{Name: "mile", Symbol: "mi", Dimension: Length, Factor: big.NewRat(1609344, 1000)},
+
+	{Name: "milligram", Symbol: "mg", Dimension: Mass, Factor: big.NewRat(1, 1000)},
+	{Name: "gram", Symbol: "g", Dimension: Mass, Factor: big.NewRat(1, 1)},
+	{Name: "kilogram", Symbol: "kg", Dimension: Mass, Factor: big.NewRat(1000, 1)},
+	{Name: "tonne", Symbol: "t", Dimension: Mass, Factor: big.NewRat(1000000, 1)},
+	{Name: "ounce", Symbol: "oz", Dimension: Mass, Factor: big.NewRat(28349523125, 1000000000)},
+	{Name: "pound", Symbol: "lb", Dimension: Mass, Factor: big.NewRat(45359237, 100000)},
+
+	{Name: "millisecond", Symbol: "ms", Dimension: Time, Factor: big.NewRat(1, 1000)},
+	{Name: "second", Symbol: "s", Dimension: Time, Factor: big.NewRat(1, 1)},
+	{Name: "minute", Symbol: "min", Dimension: Time, Factor: big.NewRat(60, 1)},
+	{Name: "hour", Symbol: "h", Dimension: Time, Factor: big.NewRat(3600, 1)},
+	{Name: "day", Symbol: "d", Dimension: Time, Factor: big.NewRat(86400, 1)},
+	{Name: "week", Symbol: "wk", Dimension: Time, Factor: big.NewRat(604800, 1)},
+
+	{Name: "bit", Symbol: "bit", Dimension: DataSize, Factor: big.NewRat(1, 8)},
+	{Name: "byte", Symbol: "B", Dimension: DataSize, Factor: big.NewRat(1, 1)},
+	{Name: "kilobyte", Symbol: "kB", Dimension: DataSize, Factor: big.NewRat(1000, 1)},
+	{Name: "megabyte", Symbol: "MB", Dimension: DataSize, Factor: big.NewRat(1000000, 1)},
+	{Name: "gigabyte", Symbol: "GB", Dimension: DataSize, Factor: big.NewRat(1000000000, 1)},
+	{Name: "terabyte", Symbol: "TB", Dimension: DataSize, Factor: big.NewRat(1000000000000, 1)},
+	{Name: "kibibyte", Symbol: "KiB", Dimension: DataSize, Factor: big.NewRat(1<<10, 1)},
+	{Name: "mebibyte", Symbol: "MiB", Dimension: DataSize, Factor: big.NewRat(1<<20, 1)},
+	{Name: "gibibyte", Symbol: "GiB", Dimension: DataSize, Factor: big.NewRat(1<<30, 1)},
+}
+
+func lookupUnit(name string) (*Unit, bool) {
+	for _, unit := range Units {
+		if unit.Name == name {
+			return unit, true
+		}
+	}
+	return nil, false
+}
+
+func lookupUnitSymbol(symbol string) (*Unit, bool) {
+	for _, unit := range Units {
+		if unit.Symbol == symbol {
+			return unit, true
+		}
+	}
+	return nil, false
+}
+
+// quantity is a number measured in a unit. A plain number taking part in an
+// operation with a quantity is wrapped in a quantity without a unit, so that
+// the operation is carried out by the quantity. Results without a unit are
+// returned as plain numbers again.
+type quantity struct {
+	value      Number
+	unit       *Unit
+	arithmetic Arithmetic
+}
+
+func newQuantity(value Number, unit *Unit, arithmetic Arithmetic) Number {
+	if unit == nil {
+		return value
+	}
+	return &quantity{value: value, unit: unit, arithmetic: arithmetic}
+}
+
+// withUnits wraps the plain numbers among the operands when any of them is a
+// quantity or a date. Operands without either among them are returned as
+// they are, and dates are never wrapped.
+func withUnits(operands []Number) []Number {
+	var arithmetic Arithmetic
+	found := false
+	for _, operand := range operands {
+		switch o := operand.(type) {
+		case *quantity:
+			arithmetic, found = o.arithmetic, true
+		case *date:
+			arithmetic, found = o.arithmetic, true
+		}
+	}
+	if !found {
+		return operands
+	}
+
+	wrapped := make([]Number, 0, len(operands))
+	for _, operand := range operands {
+		if isDate(operand) {
+			wrapped = append(wrapped, operand)
+		} else {
+			wrapped = append(wrapped, asQuantity(operand, arithmetic))
+		}
+	}
+	return wrapped
+}
+
+func unitOf(number Number) *Unit {
+	if q, ok := number.(*quantity); ok {
+		return q.unit
+	}
+	return nil
+}
+
+func asQuantity(number Number, arithmetic Arithmetic) *quantity {
+	if q, ok := number.(*quantity); ok {
+		return q
+	}
+	return &quantity{value: number, arithmetic: arithmetic}
+}
+
+// sameDimension reports whether two numbers measure the same dimension. Two
+// plain numbers have the same dimension, a plain number and a quantity don't,
+// and dates only share a dimension with other dates.
+func sameDimension(left, right Number) bool {
+	if isDate(left) || isDate(right) {
+		return isDate(left) && isDate(right)
+	}
+
+	leftUnit, rightUnit := unitOf(left), unitOf(right)
+	if leftUnit == nil || rightUnit == nil {
+		return leftUnit == rightUnit
+	}
+	return leftUnit.Dimension == rightUnit.Dimension
+}
+
+func haveSameDimension(operands []Number) bool {
+	for _, operand := range operands[1:] {
+		if !sameDimension(operands[0], operand) {
+			return false
+		}
+	}
+	return true
+}
+
+// convert returns the value of a quantity in another unit of its dimension.
+// The conversion is exact, so the integer arithmetics report a fractional
+// result as ErrDomain.
+func (q *quantity) convert(unit *Unit) (Number, error) {
+	if q.unit == unit {
+		return q.value, nil
+	}
+
+	value := exactValue(q.value)
+	value.Mul(value, q.unit.Factor)
+	value.Quo(value, unit.Factor)
+
+	return exactNumber(value, q.arithmetic)
+}
+
+// convertTo converts a quantity to another unit of its dimension.
+func convertTo(number Number, unit *Unit) (Number, error) {
+	q, ok := number.(*quantity)
+	if !ok || q.unit.Dimension != unit.Dimension {
+		return nil, ErrDimensionMismatch
+	}
+
+	value, err := q.convert(unit)
+	if err != nil {
+		return nil, err
+	}
+	return newQuantity(value, unit, q.arithmetic), nil
+}
+
+// common returns the values of two quantities of the same dimension in the
+// smaller of their units, so that "5 kilometers plus 300 meters" is added in
+// meters and stays whole.
+func (q *quantity) common(other Number) (Number, Number, *Unit, error) {
+	if isDate(other) {
+		return nil, nil, nil, ErrDimensionMismatch
+	}
+
+	o := asQuantity(other, q.arithmetic)
+	if q.unit == nil || o.unit == nil {
+		if q.unit != o.unit {
+			return nil, nil, nil, ErrDimensionMismatch
+		}
+		return q.value, o.value, nil, nil
+	}
+	if q.unit.Dimension != o.unit.Dimension {
+		return nil, nil, nil, ErrDimensionMismatch
+	}
+
+	unit := q.unit
+	if o.unit.Factor.Cmp(unit.Factor) < 0 {
+		unit = o.unit
+	}
+
+	left, err := q.convert(unit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	right, err := o.convert(unit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return left, right, unit, nil
+}
+
+func (q *quantity) additive(other Number, apply func(Number, Number) (Number, error)) (Number, error) {
+	left, right, unit, err := q.common(other)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := apply(left, right)
+	if err != nil {
+		return nil, err
+	}
+	return newQuantity(result, unit, q.arithmetic), nil
+}
+
+// Add adds two quantities of the same dimension, or moves a date by a
+// duration when the other operand is a date.
+func (q *quantity) Add(other Number) (Number, error) {
+	if d, ok := other.(*date); ok {
+		return d.Add(q)
+	}
+	return q.additive(other, Number.Add)
+}
+
+func (q *quantity) Sub(other Number) (Number, error) {
+	return q.additive(other, Number.Sub)
+}
+
+func (q *quantity) Mod(other Number) (Number, error) {
+	return q.additive(other, Number.Mod)
+}
+
+// Mul scales a quantity by a plain number. The product of two quantities
+// would need a compound unit such as the square meter, so it is reported as
+// ErrDimensionMismatch.
+func (q *quantity) Mul(other Number) (Number, error) {
+	if isDate(other) {
+		return nil, ErrDimensionMismatch
+	}
+
+	o := asQuantity(other, q.arithmetic)
+	if q.unit != nil && o.unit != nil {
+		return nil, ErrDimensionMismatch
+	}
+
+	unit := q.unit
+	if unit == nil {
+		unit = o.unit
+	}
+
+	result, err := q.value.Mul(o.value)
+	if err != nil {
+		return nil, err
+	}
+	return newQuantity(result, unit, q.arithmetic), nil
+}
+
+// Div divides a quantity by a plain number, or by another quantity of the
+// same dimension, in which case the result is their ratio as a plain number.
+func (q *quantity) Div(other Number) (Number, error) {
+	if isDate(other) {
+		return nil, ErrDimensionMismatch
+	}
+
+	o := asQuantity(other, q.arithmetic)
+	if o.unit == nil {
+		result, err := q.value.Div(o.value)
+		if err != nil {
+			return nil, err
+		}
+		return newQuantity(result, q.unit, q.arithmetic), nil
+	}
+
+	left, right, _, err := q.common(o)
+	if err != nil {
+		return nil, err
+	}
+	return left.Div(right)
+}
+
+func (q *quantity) Pow(other Number) (Number, error) {
+	if isDate(other) {
+		return nil, ErrDimensionMismatch
+	}
+
+	o := asQuantity(other, q.arithmetic)
+	if q.unit != nil || o.unit != nil {
+		return nil, ErrDimensionMismatch
+	}
+	return q.value.Pow(o.value)
+}
+
+func (q *quantity) Neg() (Number, error) {
+	result, err := q.value.Neg()
+	if err != nil {
+		return nil, err
+	}
+	return newQuantity(result, q.unit, q.arithmetic), nil
+}
+
+func (q *quantity) Sqrt() (Number, error) {
+	if q.unit != nil {
+		return nil, ErrDimensionMismatch
+	}
+	return q.value.Sqrt()
+}
+
+// Cmp compares the sizes of two quantities in the base unit of their
+// dimension. It is only meaningful for quantities of the same dimension,
+// which the caller checks with sameDimension.
+func (q *quantity) Cmp(other Number) int {
+	return q.base().Cmp(asQuantity(other, q.arithmetic).base())
+}
+
+func (q *quantity) base() *big.Rat {
+	value := exactValue(q.value)
+	if q.unit != nil {
+		value.Mul(value, q.unit.Factor)
+	}
+	return value
+}
+
+func (q *quantity) String() string {
+	if q.unit == nil {
+		return q.value.String()
+	}
+	return q.value.String() + " " + q.unit.Symbol
+}
+
+// parseQuantity reads the textual form of a quantity, e.g. "5300 m".
+func parseQuantity(value string, arithmetic Arithmetic) (Number, bool, error) {
+	literal, symbol, ok := strings.Cut(value, " ")
+	if !ok {
+		return nil, false, nil
+	}
+
+	unit, ok := lookupUnitSymbol(symbol)
+	if !ok {
+		return nil, false, nil
+	}
+
+	number, err := ParseNumber(literal, arithmetic)
+	if err != nil {
+		return nil, true, err
+	}
+	return newQuantity(number, unit, arithmetic), true, nil
+}
@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/VitoNaychev/eval-web-service/service"
+)
+
+// DefaultSessionTTL is how long a session is kept after it was last used.
+const DefaultSessionTTL = 24 * time.Hour
+
+// InMemorySessionRepository keeps sessions in memory and forgets the ones
+// that haven't been read or saved for longer than its ttl, so that the
+// sessions of clients that went away don't pile up.
+type InMemorySessionRepository struct {
+	sessions  map[string]sessionEntry
+	ttl       time.Duration
+	nextSweep time.Time
+	mu        sync.Mutex
+}
+
+type sessionEntry struct {
+	session service.Session
+	expires time.Time
+}
+
+func NewInMemorySessionRepository(ttl time.Duration) *InMemorySessionRepository {
+	return &InMemorySessionRepository{
+		sessions: make(map[string]sessionEntry),
+		ttl:      ttl,
+	}
+}
+
+func (repo *InMemorySessionRepository) Get(id string) (service.Session, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := repo.sessions[id]
+	if !ok || now.After(entry.expires) {
+		delete(repo.sessions, id)
+		return service.Session{}, nil
+	}
+
+	entry.expires = now.Add(repo.ttl)
+	repo.sessions[id] = entry
+
+	return entry.session, nil
+}
+
+func (repo *InMemorySessionRepository) Save(id string, session service.Session) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := time.Now()
+	repo.sweep(now)
+	repo.sessions[id] = sessionEntry{
+		session: session,
+		expires: now.Add(repo.ttl),
+	}
+
+	return nil
+}
+
+// sweep forgets the expired sessions. It goes through the sessions at most
+// once per ttl, so a session is forgotten within two ttls of its last use
+// whether or not its id is seen again.
+func (repo *InMemorySessionRepository) sweep(now time.Time) {
+	if now.Before(repo.nextSweep) {
+		return
+	}
+
+	for id, entry := range repo.sessions {
+		if now.After(entry.expires) {
+			delete(repo.sessions, id)
+		}
+	}
+	repo.nextSweep = now.Add(repo.ttl)
+}
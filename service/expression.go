@@ -7,17 +7,21 @@ import (
 type ExpressionService struct {
 	interp        Interpreter
 	exprErrorRepo ExprErrorRepository
+	sessionRepo   SessionRepository
+	sessionLocks  *sessionLocks
 }
 
-func NewExpressionService(interp Interpreter, exprErrorRepo ExprErrorRepository) *ExpressionService {
+func NewExpressionService(interp Interpreter, exprErrorRepo ExprErrorRepository, sessionRepo SessionRepository) *ExpressionService {
 	return &ExpressionService{
 		interp:        interp,
 		exprErrorRepo: exprErrorRepo,
+		sessionRepo:   sessionRepo,
+		sessionLocks:  newSessionLocks(),
 	}
 }
 
-func (e *ExpressionService) Validate(expr string) (bool, error) {
-	isValid, interpErr := e.interp.Validate(expr)
+func (e *ExpressionService) Validate(expr string, opts ValidateOptions) (bool, error) {
+	isValid, interpErr := e.interp.Validate(expr, opts)
 	if isValid {
 		return isValid, nil
 	}
@@ -30,18 +34,64 @@ func (e *ExpressionService) Validate(expr string) (bool, error) {
 	return false, interpErr
 }
 
-func (e *ExpressionService) Evaluate(expr string) (int, error) {
-	result, interpErr := e.interp.Evaluate(expr)
-	if interpErr == nil {
-		return result, nil
+// EvaluateSentences evaluates each sentence of an input in order, e.g. both
+// questions of "What is 1 plus 1? What is 2 plus 2?". When opts names a
+// session, the sentences see the variables and the previous result of the
+// session, and the result of the last sentence that evaluates successfully
+// and the variables assigned by then are saved to the session. The answer to
+// a yes/no question isn't a number or a date, so it doesn't replace the
+// previous result. The error of each sentence that fails is recorded against
+// the text of that sentence, while errors that concern the whole input, such
+// as an unsupported locale, are returned instead. The requests of a session
+// are evaluated one at a time, so none of them loses the variables another
+// one assigns.
+func (e *ExpressionService) EvaluateSentences(input string, opts EvaluateOptions) ([]SentenceResult, error) {
+	if opts.Session != "" {
+		unlock := e.sessionLocks.lock(opts.Session)
+		defer unlock()
+
+		session, err := e.sessionRepo.Get(opts.Session)
+		if err != nil {
+			return nil, NewExpressionServiceError(err.Error())
+		}
+		opts.Variables = session.Variables
+		opts.Previous = session.Previous
 	}
 
-	err := e.recordExpressionError(expr, MethodEvaluate, interpErr)
-	if err != nil {
-		return -1, err
+	results, interpErr := e.interp.EvaluateSentences(input, opts)
+	if interpErr != nil {
+		err := e.recordExpressionError(input, MethodEvaluate, interpErr)
+		if err != nil {
+			return nil, err
+		}
+		return nil, interpErr
+	}
+
+	var last *Result
+	previous := opts.Previous
+	for i, result := range results {
+		if result.Err != nil {
+			err := e.recordExpressionError(result.Sentence, MethodEvaluate, result.Err)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		last = &results[i].Result
+		if last.Type != ResultBoolean {
+			previous = last.Value
+		}
+	}
+
+	if last != nil {
+		err := e.saveSession(opts.Session, *last, previous)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return -1, interpErr
+	return results, nil
 }
 
 func (e *ExpressionService) GetExpressionErrors() ([]ExpressionError, error) {
@@ -53,6 +103,55 @@ func (e *ExpressionService) GetExpressionErrors() ([]ExpressionError, error) {
 	return exprErrors, nil
 }
 
+// GetVariables returns the variables assigned in a session.
+func (e *ExpressionService) GetVariables(session string) (map[string]string, error) {
+	s, err := e.sessionRepo.Get(session)
+	if err != nil {
+		return nil, NewExpressionServiceError(err.Error())
+	}
+
+	return s.Variables, nil
+}
+
+// ClearVariables forgets the variables assigned in a session. The previous
+// result of the session is kept.
+func (e *ExpressionService) ClearVariables(id string) error {
+	unlock := e.sessionLocks.lock(id)
+	defer unlock()
+
+	session, err := e.sessionRepo.Get(id)
+	if err != nil {
+		return NewExpressionServiceError(err.Error())
+	}
+
+	err = e.sessionRepo.Save(id, Session{Previous: session.Previous})
+	if err != nil {
+		return NewExpressionServiceError(err.Error())
+	}
+
+	return nil
+}
+
+func (e *ExpressionService) saveSession(id string, result Result, previous string) error {
+	if id == "" {
+		return nil
+	}
+
+	if result.Type != ResultBoolean {
+		previous = result.Value
+	}
+
+	err := e.sessionRepo.Save(id, Session{
+		Variables: result.Variables,
+		Previous:  previous,
+	})
+	if err != nil {
+		return NewExpressionServiceError(err.Error())
+	}
+
+	return nil
+}
+
 func (e *ExpressionService) recordExpressionError(expr string, method MethodType, interpErr error) error {
 	errorType, err := evalServiceErrorToErrorType(interpErr)
 	if err != nil {
@@ -81,6 +180,24 @@ func evalServiceErrorToErrorType(err error) (ErrorType, error) {
 		return ErrorTypeUnsupportedOperand, nil
 	case errors.Is(err, ErrInvalidSyntax):
 		return ErrorTypeInvalidSyntax, nil
+	case errors.Is(err, ErrDivisionByZero):
+		return ErrorTypeDivisionByZero, nil
+	case errors.Is(err, ErrOverflow):
+		return ErrorTypeOverflow, nil
+	case errors.Is(err, ErrDomain):
+		return ErrorTypeDomain, nil
+	case errors.Is(err, ErrUnsupportedLocale):
+		return ErrorTypeUnsupportedLocale, nil
+	case errors.Is(err, ErrUndefinedVariable):
+		return ErrorTypeUndefinedVariable, nil
+	case errors.Is(err, ErrNoPreviousResult):
+		return ErrorTypeNoPreviousResult, nil
+	case errors.Is(err, ErrDimensionMismatch):
+		return ErrorTypeDimensionMismatch, nil
+	case errors.Is(err, ErrInvalidDate):
+		return ErrorTypeInvalidDate, nil
+	case errors.Is(err, ErrUnknownTimeZone):
+		return ErrorTypeUnknownTimeZone, nil
 	default:
 		return ErrorType(-1), NewUnsupportedInterpreterError(err.Error())
 	}
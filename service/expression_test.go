@@ -3,6 +3,9 @@ package service_test
 import (
 	"errors"
 	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/VitoNaychev/eval-web-service/service"
@@ -10,17 +13,22 @@ import (
 )
 
 type StubInterpreter struct {
-	isValid bool
-	result  int
-	err     error
+	isValid   bool
+	sentences []service.SentenceResult
+	err       error
+
+	spyOpts         service.EvaluateOptions
+	spyValidateOpts service.ValidateOptions
 }
 
-func (s *StubInterpreter) Validate(q string) (bool, error) {
+func (s *StubInterpreter) Validate(q string, opts service.ValidateOptions) (bool, error) {
+	s.spyValidateOpts = opts
 	return s.isValid, s.err
 }
 
-func (s *StubInterpreter) Evaluate(q string) (int, error) {
-	return s.result, s.err
+func (s *StubInterpreter) EvaluateSentences(q string, opts service.EvaluateOptions) ([]service.SentenceResult, error) {
+	s.spyOpts = opts
+	return s.sentences, s.err
 }
 
 func (s *StubInterpreter) Exec(q string) (int, error) {
@@ -43,6 +51,63 @@ func (s *StubErrorRepository) GetAll() ([]service.ExpressionError, error) {
 	return s.exprErrors, s.err
 }
 
+type StubSessionRepository struct {
+	session service.Session
+	err     error
+
+	spyID      string
+	spySession service.Session
+}
+
+func (s *StubSessionRepository) Get(id string) (service.Session, error) {
+	s.spyID = id
+	return s.session, s.err
+}
+
+func (s *StubSessionRepository) Save(id string, session service.Session) error {
+	s.spyID = id
+	s.spySession = session
+	return s.err
+}
+
+// MapSessionRepository keeps sessions in a map, for the tests that make
+// several requests in the same session.
+type MapSessionRepository struct {
+	sessions map[string]service.Session
+	mu       sync.Mutex
+}
+
+func (m *MapSessionRepository) Get(id string) (service.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sessions[id], nil
+}
+
+func (m *MapSessionRepository) Save(id string, session service.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[id] = session
+	return nil
+}
+
+// CountingInterpreter answers every input by incrementing the variable n of
+// the session, yielding in between so that requests made at once interleave.
+type CountingInterpreter struct{}
+
+func (c *CountingInterpreter) Validate(q string, opts service.ValidateOptions) (bool, error) {
+	return true, nil
+}
+
+func (c *CountingInterpreter) EvaluateSentences(q string, opts service.EvaluateOptions) ([]service.SentenceResult, error) {
+	n, _ := strconv.Atoi(opts.Variables["n"])
+	runtime.Gosched()
+
+	value := strconv.Itoa(n + 1)
+	return []service.SentenceResult{
+		{Sentence: q, Result: service.Result{Value: value, Variables: map[string]string{"n": value}}},
+	}, nil
+}
+
 func TestValidate(t *testing.T) {
 	t.Run("returns true on valid expression", func(t *testing.T) {
 		expression := "What is 5?"
@@ -52,14 +117,32 @@ func TestValidate(t *testing.T) {
 			isValid: wantValid,
 		}
 		repo := &StubErrorRepository{}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		gotValid, err := exprSvc.Validate(expression)
+		gotValid, err := exprSvc.Validate(expression, service.ValidateOptions{})
 		assert.RequireNoError(t, err)
 
 		assert.Equal(t, gotValid, wantValid)
 	})
 
+	t.Run("passes validation options to interpreter", func(t *testing.T) {
+		expression := "Was ist 5?"
+		wantOpts := service.ValidateOptions{
+			Locales: []string{"de"},
+		}
+
+		interp := &StubInterpreter{
+			isValid: true,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, err := exprSvc.Validate(expression, wantOpts)
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, interp.spyValidateOpts, wantOpts)
+	})
+
 	t.Run("returns interpreter error on invalid expression", func(t *testing.T) {
 		expression := "example expression"
 		wantValid := false
@@ -70,9 +153,9 @@ func TestValidate(t *testing.T) {
 			err:     wantErr,
 		}
 		repo := &StubErrorRepository{}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		gotValid, gotErr := exprSvc.Validate(expression)
+		gotValid, gotErr := exprSvc.Validate(expression, service.ValidateOptions{})
 		assert.Equal(t, gotValid, wantValid)
 		assert.Equal(t, gotErr, wantErr)
 	})
@@ -92,10 +175,36 @@ func TestValidate(t *testing.T) {
 			err:     err,
 		}
 		repo := &StubErrorRepository{}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, _ = exprSvc.Validate(expression, service.ValidateOptions{})
+
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists errors with a diagnostic under their type", func(t *testing.T) {
+		expression := "What is 5 plus plus 3?"
+		err := service.NewDiagnosticError(service.ErrInvalidSyntax, service.Diagnostic{
+			Offset:   15,
+			Length:   4,
+			Text:     "plus",
+			Expected: []string{"number"},
+		})
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodValidate,
+			Type:       service.ErrorTypeInvalidSyntax,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		_, _ = exprSvc.Validate(expression)
+		_, gotErr := exprSvc.Validate(expression, service.ValidateOptions{})
 
+		assert.Equal(t, gotErr, err)
 		assert.Equal(t, repo.spyExprError, wantExprError)
 	})
 
@@ -110,9 +219,9 @@ func TestValidate(t *testing.T) {
 			err:     err,
 		}
 		repo := &StubErrorRepository{}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		_, gotErr := exprSvc.Validate(expression)
+		_, gotErr := exprSvc.Validate(expression, service.ValidateOptions{})
 
 		assert.ErrorType[*service.UnsupportedInterpreterError](t, gotErr)
 		assert.Equal(t, gotErr.Error(), wantErrMessage)
@@ -131,30 +240,49 @@ func TestValidate(t *testing.T) {
 		repo := &StubErrorRepository{
 			err: errors.New(repoErrMessage),
 		}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		_, gotErr := exprSvc.Validate(expression)
+		_, gotErr := exprSvc.Validate(expression, service.ValidateOptions{})
 
 		assert.ErrorType[*service.ExpressionServiceError](t, gotErr)
 		assert.Equal(t, gotErr.Error(), repoErrMessage)
 	})
 }
 
-func TestEvaluate(t *testing.T) {
+func TestEvaluateSentences(t *testing.T) {
 	t.Run("returns result on valid expression", func(t *testing.T) {
 		expression := "What is 5?"
-		wantResult := 5
+		wantResult := service.Result{
+			Value:      "5",
+			Arithmetic: service.ArithmeticInteger,
+		}
 
 		interp := &StubInterpreter{
-			result: wantResult,
+			sentences: []service.SentenceResult{{Sentence: expression, Result: wantResult}},
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		gotResults, err := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotResults, []service.SentenceResult{{Sentence: expression, Result: wantResult}})
+	})
+
+	t.Run("passes evaluation options to interpreter", func(t *testing.T) {
+		expression := "What is 7 divided by 2?"
+		wantOpts := service.EvaluateOptions{
+			Arithmetic: service.ArithmeticRational,
 		}
+
+		interp := &StubInterpreter{}
 		repo := &StubErrorRepository{}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		gotResult, err := exprSvc.Evaluate(expression)
+		_, err := exprSvc.EvaluateSentences(expression, wantOpts)
 		assert.RequireNoError(t, err)
 
-		assert.Equal(t, gotResult, wantResult)
+		assert.Equal(t, interp.spyOpts, wantOpts)
 	})
 
 	t.Run("returns interpreter error on invalid expression", func(t *testing.T) {
@@ -165,9 +293,9 @@ func TestEvaluate(t *testing.T) {
 			err: wantErr,
 		}
 		repo := &StubErrorRepository{}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		_, gotErr := exprSvc.Evaluate(expression)
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
 		assert.Equal(t, gotErr, wantErr)
 	})
 
@@ -184,10 +312,335 @@ func TestEvaluate(t *testing.T) {
 			err: err,
 		}
 		repo := &StubErrorRepository{}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, _ = exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists division by zero in repository", func(t *testing.T) {
+		expression := "What is 5 divided by 0?"
+		err := service.ErrDivisionByZero
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeDivisionByZero,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+
+		assert.Equal(t, gotErr, err)
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists overflow in repository", func(t *testing.T) {
+		expression := "What is 9999999999 multiplied by 9999999999?"
+		err := service.ErrOverflow
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeOverflow,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+
+		assert.Equal(t, gotErr, err)
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists domain error in repository", func(t *testing.T) {
+		expression := "What is the square root of -4?"
+		err := service.ErrDomain
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeDomain,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+
+		assert.Equal(t, gotErr, err)
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("passes session variables to interpreter", func(t *testing.T) {
+		expression := "What is x multiplied by 3?"
+		variables := map[string]string{"x": "5"}
+
+		interp := &StubInterpreter{}
+		repo := &StubErrorRepository{}
+		sessionRepo := &StubSessionRepository{
+			session: service.Session{Variables: variables},
+		}
+		exprSvc := service.NewExpressionService(interp, repo, sessionRepo)
+
+		_, err := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{Session: "abc"})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, sessionRepo.spyID, "abc")
+		assert.Equal(t, interp.spyOpts.Variables, variables)
+	})
+
+	t.Run("saves result and assigned variables to session", func(t *testing.T) {
+		expression := "Let x be 5."
+		result := service.Result{
+			Value:     "5",
+			Variables: map[string]string{"x": "5"},
+		}
+
+		interp := &StubInterpreter{
+			sentences: []service.SentenceResult{{Sentence: expression, Result: result}},
+		}
+		repo := &StubErrorRepository{}
+		sessionRepo := &StubSessionRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, sessionRepo)
+
+		_, err := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{Session: "abc"})
+		assert.RequireNoError(t, err)
+
+		wantSession := service.Session{
+			Variables: result.Variables,
+			Previous:  "5",
+		}
+		assert.Equal(t, sessionRepo.spySession, wantSession)
+	})
+
+	t.Run("keeps previous result of session after a yes/no question", func(t *testing.T) {
+		expression := "Is the result greater than 3?"
+		result := service.Result{
+			Value: "true",
+			Type:  service.ResultBoolean,
+		}
+
+		interp := &StubInterpreter{
+			sentences: []service.SentenceResult{{Sentence: expression, Result: result}},
+		}
+		repo := &StubErrorRepository{}
+		sessionRepo := &StubSessionRepository{
+			session: service.Session{Previous: "5"},
+		}
+		exprSvc := service.NewExpressionService(interp, repo, sessionRepo)
+
+		_, err := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{Session: "abc"})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, sessionRepo.spySession.Previous, "5")
+	})
+
+	t.Run("saves a date as the previous result of session", func(t *testing.T) {
+		expression := "What is 2024-03-01 plus 45 days?"
+		result := service.Result{
+			Value: "2024-04-15",
+			Type:  service.ResultDate,
+		}
+
+		interp := &StubInterpreter{
+			sentences: []service.SentenceResult{{Sentence: expression, Result: result}},
+		}
+		repo := &StubErrorRepository{}
+		sessionRepo := &StubSessionRepository{
+			session: service.Session{Previous: "5"},
+		}
+		exprSvc := service.NewExpressionService(interp, repo, sessionRepo)
+
+		_, err := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{Session: "abc"})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, sessionRepo.spySession.Previous, "2024-04-15")
+	})
+
+	t.Run("passes previous result of session to interpreter", func(t *testing.T) {
+		expression := "What is the result multiplied by 2?"
+
+		interp := &StubInterpreter{}
+		repo := &StubErrorRepository{}
+		sessionRepo := &StubSessionRepository{
+			session: service.Session{Previous: "8"},
+		}
+		exprSvc := service.NewExpressionService(interp, repo, sessionRepo)
+
+		_, err := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{Session: "abc"})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, interp.spyOpts.Previous, "8")
+	})
+
+	t.Run("doesn't touch sessions without a session id", func(t *testing.T) {
+		expression := "Let x be 5."
+
+		interp := &StubInterpreter{
+			sentences: []service.SentenceResult{{Sentence: expression, Result: service.Result{Value: "5"}}},
+		}
+		repo := &StubErrorRepository{}
+		sessionRepo := &StubSessionRepository{
+			err: errors.New("repo error"),
+		}
+		exprSvc := service.NewExpressionService(interp, repo, sessionRepo)
+
+		_, err := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+		assert.RequireNoError(t, err)
+	})
+
+	t.Run("wraps session repository errors in EvalServiceError", func(t *testing.T) {
+		expression := "What is x?"
+		repoErrMessage := "repo error"
+
+		interp := &StubInterpreter{}
+		repo := &StubErrorRepository{}
+		sessionRepo := &StubSessionRepository{
+			err: errors.New(repoErrMessage),
+		}
+		exprSvc := service.NewExpressionService(interp, repo, sessionRepo)
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{Session: "abc"})
+
+		assert.ErrorType[*service.ExpressionServiceError](t, gotErr)
+		assert.Equal(t, gotErr.Error(), repoErrMessage)
+	})
+
+	t.Run("persists a group error as invalid syntax and returns its message", func(t *testing.T) {
+		expression := "What is (1 plus 2?"
+		err := service.ErrUnclosedGroup
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeInvalidSyntax,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+
+		assert.ErrorIs(t, gotErr, service.ErrInvalidSyntax)
+		assert.Equal(t, gotErr.Error(), "invalid syntax: unclosed group")
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists undefined variable in repository", func(t *testing.T) {
+		expression := "What is y?"
+		err := service.ErrUndefinedVariable
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeUndefinedVariable,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+
+		assert.Equal(t, gotErr, err)
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists missing previous result in repository", func(t *testing.T) {
+		expression := "What is the result plus 1?"
+		err := service.ErrNoPreviousResult
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeNoPreviousResult,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
+
+		assert.Equal(t, gotErr, err)
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists dimension mismatch in repository", func(t *testing.T) {
+		expression := "What is 5 meters plus 3 seconds?"
+		err := service.ErrDimensionMismatch
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeDimensionMismatch,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		_, _ = exprSvc.Evaluate(expression)
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
 
+		assert.Equal(t, gotErr, err)
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists unsupported locale error in repository", func(t *testing.T) {
+		expression := "Quanto fa 2 più 2?"
+		err := service.ErrUnsupportedLocale
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeUnsupportedLocale,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{Locales: []string{"it"}})
+
+		assert.Equal(t, gotErr, err)
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists unknown time zone error in repository", func(t *testing.T) {
+		expression := "What is 2024-03-01 plus 1 day?"
+		err := service.ErrUnknownTimeZone
+		wantExprError := service.ExpressionError{
+			Expression: expression,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeUnknownTimeZone,
+		}
+
+		interp := &StubInterpreter{
+			err: err,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{TimeZone: "Mars/Olympus"})
+
+		assert.Equal(t, gotErr, err)
 		assert.Equal(t, repo.spyExprError, wantExprError)
 	})
 
@@ -200,9 +653,9 @@ func TestEvaluate(t *testing.T) {
 			err: err,
 		}
 		repo := &StubErrorRepository{}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		_, gotErr := exprSvc.Evaluate(expression)
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
 
 		assert.ErrorType[*service.UnsupportedInterpreterError](t, gotErr)
 		assert.Equal(t, gotErr.Error(), wantErrMessage)
@@ -219,13 +672,138 @@ func TestEvaluate(t *testing.T) {
 		repo := &StubErrorRepository{
 			err: errors.New(repoErrMessage),
 		}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
-		_, gotErr := exprSvc.Evaluate(expression)
+		_, gotErr := exprSvc.EvaluateSentences(expression, service.EvaluateOptions{})
 
 		assert.ErrorType[*service.ExpressionServiceError](t, gotErr)
 		assert.Equal(t, gotErr.Error(), repoErrMessage)
 	})
+	t.Run("returns the result of each sentence", func(t *testing.T) {
+		input := "What is 1 plus 1? What is 2 plus 2?"
+		wantResults := []service.SentenceResult{
+			{Sentence: "What is 1 plus 1?", Result: service.Result{Value: "2"}},
+			{Sentence: "What is 2 plus 2?", Result: service.Result{Value: "4"}},
+		}
+
+		interp := &StubInterpreter{
+			sentences: wantResults,
+		}
+		exprSvc := service.NewExpressionService(interp, &StubErrorRepository{}, &StubSessionRepository{})
+
+		gotResults, err := exprSvc.EvaluateSentences(input, service.EvaluateOptions{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, gotResults, wantResults)
+	})
+
+	t.Run("persists the failing sentence in repository", func(t *testing.T) {
+		input := "What is 1 plus 1? What is 5 divided by 0?"
+		wantExprError := service.ExpressionError{
+			Expression: "What is 5 divided by 0?",
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeDivisionByZero,
+		}
+
+		interp := &StubInterpreter{
+			sentences: []service.SentenceResult{
+				{Sentence: "What is 1 plus 1?", Result: service.Result{Value: "2"}},
+				{Sentence: "What is 5 divided by 0?", Err: service.ErrDivisionByZero},
+			},
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, err := exprSvc.EvaluateSentences(input, service.EvaluateOptions{})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("persists an error of the whole input in repository", func(t *testing.T) {
+		input := "What is 1 plus 1? What is 2 plus 2?"
+		wantErr := service.ErrUnsupportedLocale
+		wantExprError := service.ExpressionError{
+			Expression: input,
+			Method:     service.MethodEvaluate,
+			Type:       service.ErrorTypeUnsupportedLocale,
+		}
+
+		interp := &StubInterpreter{
+			err: wantErr,
+		}
+		repo := &StubErrorRepository{}
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
+
+		_, gotErr := exprSvc.EvaluateSentences(input, service.EvaluateOptions{})
+		assert.ErrorIs(t, gotErr, wantErr)
+
+		assert.Equal(t, repo.spyExprError, wantExprError)
+	})
+
+	t.Run("saves the last result and variables to session", func(t *testing.T) {
+		input := "Let x be 5. What is x times 2? Is x greater than 3? What is y?"
+		variables := map[string]string{"x": "5"}
+
+		interp := &StubInterpreter{
+			sentences: []service.SentenceResult{
+				{Sentence: "Let x be 5.", Result: service.Result{Value: "5", Variables: variables}},
+				{Sentence: "What is x times 2?", Result: service.Result{Value: "10", Variables: variables}},
+				{Sentence: "Is x greater than 3?", Result: service.Result{Value: "true", Type: service.ResultBoolean, Variables: variables}},
+				{Sentence: "What is y?", Err: service.ErrUndefinedVariable},
+			},
+		}
+		sessionRepo := &StubSessionRepository{}
+		exprSvc := service.NewExpressionService(interp, &StubErrorRepository{}, sessionRepo)
+
+		_, err := exprSvc.EvaluateSentences(input, service.EvaluateOptions{Session: "abc"})
+		assert.RequireNoError(t, err)
+
+		wantSession := service.Session{
+			Variables: variables,
+			Previous:  "10",
+		}
+		assert.Equal(t, sessionRepo.spySession, wantSession)
+	})
+
+	t.Run("doesn't save session when every sentence fails", func(t *testing.T) {
+		input := "What is y?"
+
+		interp := &StubInterpreter{
+			sentences: []service.SentenceResult{
+				{Sentence: "What is y?", Err: service.ErrUndefinedVariable},
+			},
+		}
+		sessionRepo := &StubSessionRepository{
+			session: service.Session{Previous: "5"},
+		}
+		exprSvc := service.NewExpressionService(interp, &StubErrorRepository{}, sessionRepo)
+
+		_, err := exprSvc.EvaluateSentences(input, service.EvaluateOptions{Session: "abc"})
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, sessionRepo.spySession, service.Session{})
+	})
+
+	t.Run("doesn't lose variables assigned by requests made at once", func(t *testing.T) {
+		requests := 50
+
+		sessionRepo := &MapSessionRepository{sessions: map[string]service.Session{}}
+		exprSvc := service.NewExpressionService(&CountingInterpreter{}, &StubErrorRepository{}, sessionRepo)
+
+		var wg sync.WaitGroup
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				exprSvc.EvaluateSentences("Let n be n plus 1.", service.EvaluateOptions{Session: "abc"})
+			}()
+		}
+		wg.Wait()
+
+		session, _ := sessionRepo.Get("abc")
+		assert.Equal(t, session.Variables["n"], strconv.Itoa(requests))
+	})
 }
 
 func TestGetExpressionErrors(t *testing.T) {
@@ -243,7 +821,7 @@ func TestGetExpressionErrors(t *testing.T) {
 		repo := &StubErrorRepository{
 			exprErrors: wantExprErrors,
 		}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
 		gotExprErrors, err := exprSvc.GetExpressionErrors()
 		assert.RequireNoError(t, err)
@@ -258,7 +836,7 @@ func TestGetExpressionErrors(t *testing.T) {
 		repo := &StubErrorRepository{
 			err: errors.New(wantErrMessage),
 		}
-		exprSvc := service.NewExpressionService(interp, repo)
+		exprSvc := service.NewExpressionService(interp, repo, &StubSessionRepository{})
 
 		_, gotErr := exprSvc.GetExpressionErrors()
 
@@ -266,3 +844,52 @@ func TestGetExpressionErrors(t *testing.T) {
 		assert.Equal(t, gotErr.Error(), wantErrMessage)
 	})
 }
+
+func TestVariables(t *testing.T) {
+	t.Run("returns the variables of a session", func(t *testing.T) {
+		wantVariables := map[string]string{"x": "5"}
+
+		sessionRepo := &StubSessionRepository{
+			session: service.Session{Variables: wantVariables},
+		}
+		exprSvc := service.NewExpressionService(&StubInterpreter{}, &StubErrorRepository{}, sessionRepo)
+
+		gotVariables, err := exprSvc.GetVariables("abc")
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, sessionRepo.spyID, "abc")
+		assert.Equal(t, gotVariables, wantVariables)
+	})
+
+	t.Run("clears the variables of a session and keeps its previous result", func(t *testing.T) {
+		sessionRepo := &StubSessionRepository{
+			session: service.Session{Variables: map[string]string{"x": "5"}, Previous: "8"},
+		}
+		exprSvc := service.NewExpressionService(&StubInterpreter{}, &StubErrorRepository{}, sessionRepo)
+
+		err := exprSvc.ClearVariables("abc")
+		assert.RequireNoError(t, err)
+
+		assert.Equal(t, sessionRepo.spyID, "abc")
+		assert.Equal(t, sessionRepo.spySession, service.Session{Previous: "8"})
+	})
+
+	t.Run("wraps session repository errors in EvalServiceError", func(t *testing.T) {
+		wantErrMessage := "repo error"
+
+		sessionRepo := &StubSessionRepository{
+			err: errors.New(wantErrMessage),
+		}
+		exprSvc := service.NewExpressionService(&StubInterpreter{}, &StubErrorRepository{}, sessionRepo)
+
+		_, gotErr := exprSvc.GetVariables("abc")
+
+		assert.ErrorType[*service.ExpressionServiceError](t, gotErr)
+		assert.Equal(t, gotErr.Error(), wantErrMessage)
+
+		gotErr = exprSvc.ClearVariables("abc")
+
+		assert.ErrorType[*service.ExpressionServiceError](t, gotErr)
+		assert.Equal(t, gotErr.Error(), wantErrMessage)
+	})
+}
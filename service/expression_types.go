@@ -4,6 +4,7 @@ import "fmt"
 
 type ExpressionServiceError struct {
 	msg string
+	err error
 }
 
 func NewExpressionServiceError(msg string) error {
@@ -12,14 +13,41 @@ func NewExpressionServiceError(msg string) error {
 	}
 }
 
+// NewSyntaxError returns an error that describes a specific syntax problem,
+// e.g. "invalid syntax: unclosed group", while still matching
+// ErrInvalidSyntax through errors.Is.
+func NewSyntaxError(msg string) error {
+	return &ExpressionServiceError{
+		msg: ErrInvalidSyntax.Error() + ": " + msg,
+		err: ErrInvalidSyntax,
+	}
+}
+
 func (e *ExpressionServiceError) Error() string {
 	return e.msg
 }
 
+func (e *ExpressionServiceError) Unwrap() error {
+	return e.err
+}
+
 var (
 	ErrNonMathQuestion      = NewExpressionServiceError("non-math question")
 	ErrUnsupportedOperation = NewExpressionServiceError("unsupported operation")
 	ErrInvalidSyntax        = NewExpressionServiceError("invalid syntax")
+	ErrDivisionByZero       = NewExpressionServiceError("division by zero")
+	ErrOverflow             = NewExpressionServiceError("overflow")
+	ErrDomain               = NewExpressionServiceError("math domain error")
+	ErrDimensionMismatch    = NewExpressionServiceError("dimension mismatch")
+	ErrInvalidDate          = NewExpressionServiceError("invalid date")
+	ErrUnknownTimeZone      = NewExpressionServiceError("unknown time zone")
+	ErrUnsupportedLocale    = NewExpressionServiceError("unsupported locale")
+	ErrUndefinedVariable    = NewExpressionServiceError("undefined variable")
+	ErrNoPreviousResult     = NewExpressionServiceError("no previous result")
+
+	ErrUnclosedGroup = NewSyntaxError("unclosed group")
+	ErrUnopenedGroup = NewSyntaxError("closing an unopened group")
+	ErrEmptyGroup    = NewSyntaxError("empty group")
 )
 
 type UnsupportedInterpreterError struct {
@@ -44,9 +72,169 @@ func (u *UnsupportedInterpreterError) As(target interface{}) bool {
 	return false
 }
 
+// Diagnostic locates the part of an expression that caused an error.
+// Suggestions lists the known words closest to a word that couldn't be
+// read, e.g. "plus" for "plsu", and Hint writes them as a question, e.g.
+// "did you mean 'plus'?".
+type Diagnostic struct {
+	Offset      int
+	Length      int
+	Text        string
+	Expected    []string
+	Suggestions []string
+	Hint        string
+}
+
+// DiagnosticError attaches a Diagnostic to one of the service errors. It
+// reports the message of the error it wraps and matches it through
+// errors.Is.
+type DiagnosticError struct {
+	err        error
+	Diagnostic Diagnostic
+}
+
+func NewDiagnosticError(err error, diagnostic Diagnostic) error {
+	return &DiagnosticError{
+		err:        err,
+		Diagnostic: diagnostic,
+	}
+}
+
+func (d *DiagnosticError) Error() string {
+	return d.err.Error()
+}
+
+func (d *DiagnosticError) Unwrap() error {
+	return d.err
+}
+
 type Interpreter interface {
-	Validate(string) (bool, error)
-	Evaluate(string) (int, error)
+	Validate(string, ValidateOptions) (bool, error)
+	EvaluateSentences(string, EvaluateOptions) ([]SentenceResult, error)
+}
+
+type Arithmetic int
+
+const (
+	ArithmeticInteger Arithmetic = iota
+	ArithmeticFloat
+	ArithmeticDecimal
+	ArithmeticRational
+	ArithmeticBigInteger
+)
+
+// Order selects the order operations are applied in. OrderPrecedence applies
+// "multiplied by" before "plus", while OrderLeftToRight keeps the original
+// semantics and applies every operation to the result of the ones before it.
+type Order int
+
+const (
+	OrderPrecedence Order = iota
+	OrderLeftToRight
+)
+
+// ValidateOptions configures how an expression is read. Locales lists the
+// language tags of the expression in order of preference. The first
+// supported one is used and "*" stands for the default language, which is
+// also used when the list is empty. EvaluateOptions reads Locales the same
+// way.
+type ValidateOptions struct {
+	Locales []string
+}
+
+// EvaluateOptions configures how an expression is evaluated. Session names
+// the session whose variables and previous result the expression can read,
+// and is left empty to evaluate the expression on its own. The service
+// passes the variables and the previous result of the session to the
+// interpreter in Variables and Previous. TimeZone is the IANA name of the
+// time zone dates are read and written in, e.g. "Europe/Sofia", and is left
+// empty for UTC. Order selects the order operations are applied in. Format
+// selects whether the Result also holds an Answer,
+// and Trace whether it also holds the Steps of the evaluation.
+type EvaluateOptions struct {
+	Arithmetic Arithmetic
+	Order      Order
+	Locales    []string
+	Session    string
+	Variables  map[string]string
+	Previous   string
+	TimeZone   string
+	Format     Format
+	Trace      bool
+}
+
+// Format selects how the result of an evaluation is presented. FormatNumber
+// only gives its value, while FormatSentence also answers with a sentence
+// such as "5 plus 3 is 8." and FormatWords with one that spells out its
+// numbers, such as "Five plus three is eight.".
+type Format int
+
+const (
+	FormatNumber Format = iota
+	FormatSentence
+	FormatWords
+)
+
+// ResultType tells whether a Result holds a number, a date or the answer to
+// a yes/no question.
+type ResultType int
+
+const (
+	ResultNumber ResultType = iota
+	ResultBoolean
+	ResultDate
+)
+
+// Result is the value of an evaluated expression. Value is "true" or "false"
+// when Type is ResultBoolean. Variables holds the variables in scope after
+// the evaluation, including any it assigned. Answer is the result written
+// as a sentence, and is only set when the EvaluateOptions ask for one, as
+// are the Steps.
+type Result struct {
+	Value      string
+	Type       ResultType
+	Arithmetic Arithmetic
+	Variables  map[string]string
+	Answer     string
+	Steps      []Step
+}
+
+// Step is one operation applied while evaluating an expression. Text shows
+// the working, e.g. "2 plus 3 = 5", and Operator, Operands and Result its
+// parts. Offset and Length locate the operator in the input, counted in
+// bytes.
+type Step struct {
+	Text     string
+	Operator string
+	Operands []string
+	Result   string
+	Offset   int
+	Length   int
+}
+
+// SentenceResult is the outcome of one sentence of an input holding several
+// questions. Sentence is the text of the sentence in the input, and either
+// Result or Err is set.
+type SentenceResult struct {
+	Sentence string
+	Result   Result
+	Err      error
+}
+
+// Session is the state shared by the evaluations made with the same session
+// id. Previous is the value of the last successful evaluation that produced
+// a number or a date.
+type Session struct {
+	Variables map[string]string
+	Previous  string
+}
+
+// SessionRepository stores sessions by their id. Get returns an empty
+// session for an id it doesn't know, and a repository may forget sessions
+// that haven't been used for a while.
+type SessionRepository interface {
+	Get(id string) (Session, error)
+	Save(id string, session Session) error
 }
 
 type ExprErrorRepository interface {
@@ -60,6 +248,15 @@ const (
 	ErrorTypeNonMathQuestion ErrorType = iota
 	ErrorTypeUnsupportedOperand
 	ErrorTypeInvalidSyntax
+	ErrorTypeDivisionByZero
+	ErrorTypeOverflow
+	ErrorTypeDomain
+	ErrorTypeUnsupportedLocale
+	ErrorTypeUndefinedVariable
+	ErrorTypeNoPreviousResult
+	ErrorTypeDimensionMismatch
+	ErrorTypeInvalidDate
+	ErrorTypeUnknownTimeZone
 )
 
 type MethodType int
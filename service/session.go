@@ -0,0 +1,49 @@
+package service
+
+import "sync"
+
+// sessionLocks serialises the requests made in each session, so that a
+// request can't save the session over the variables another one assigned
+// after it read the session. The lock of a session is dropped once no
+// request holds or waits for it.
+type sessionLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sessionLock
+}
+
+type sessionLock struct {
+	sync.Mutex
+	requests int
+}
+
+func newSessionLocks() *sessionLocks {
+	return &sessionLocks{
+		locks: make(map[string]*sessionLock),
+	}
+}
+
+// lock locks the session with the given id and returns the function that
+// unlocks it.
+func (s *sessionLocks) lock(id string) func() {
+	s.mu.Lock()
+	lock, ok := s.locks[id]
+	if !ok {
+		lock = &sessionLock{}
+		s.locks[id] = lock
+	}
+	lock.requests++
+	s.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		s.mu.Lock()
+		lock.requests--
+		if lock.requests == 0 {
+			delete(s.locks, id)
+		}
+		s.mu.Unlock()
+	}
+}
@@ -38,3 +38,11 @@ func Equal(t testing.TB, got, want interface{}) {
 		t.Errorf("got %v want %v", got, want)
 	}
 }
+
+func ErrorIs(t testing.TB, got, want error) {
+	t.Helper()
+
+	if !errors.Is(got, want) {
+		t.Errorf("got error %v want %v", got, want)
+	}
+}